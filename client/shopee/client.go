@@ -12,7 +12,6 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -41,6 +40,11 @@ type Client struct {
 	retryTimes int
 	retryDelay time.Duration
 	timeout    time.Duration
+
+	// Open Platform 环境与签名使用的 partner
+	openPlatformEnv OpenPlatformEnv
+	partnerId       string
+	partnerKey      string
 }
 
 type ClientOption func(*Client)
@@ -67,6 +71,15 @@ func WithRetry(times int, delay time.Duration) ClientOption {
 	}
 }
 
+// WithOpenPlatformEnv 切换 Open Platform 环境，同时切换请求地址与 partner
+func WithOpenPlatformEnv(env OpenPlatformEnv) ClientOption {
+	return func(c *Client) {
+		c.openPlatformEnv = env
+		c.baseURL = env.BaseURL()
+		c.partnerId, c.partnerKey = env.Partner()
+	}
+}
+
 // InitShopeeClient 创建新的客户端
 func InitShopeeClient() {
 	once.Do(func() {
@@ -88,6 +101,7 @@ func InitShopeeClient() {
 			retryDelay: 2 * time.Second,
 			timeout:    30 * time.Second,
 		}
+		applyOpenPlatformEnvFromOS(shopeeClientForTw)
 
 		shopeeClient = &Client{
 			baseURL: BaseSellerURL,
@@ -115,6 +129,27 @@ func GetTwShopeeClient() *Client {
 	return shopeeClientForTw
 }
 
+// SetTwShopeeClient 替换全局 Open Platform 客户端，例如切换到测试环境
+func SetTwShopeeClient(client *Client) {
+	mu.Lock()
+	defer mu.Unlock()
+	shopeeClientForTw = client
+}
+
+// applyOpenPlatformEnvFromOS 根据环境变量选择 Open Platform 环境，并允许覆盖请求地址
+func applyOpenPlatformEnvFromOS(c *Client) {
+	env, err := ParseOpenPlatformEnv(os.Getenv(EnvKeyOpenPlatformEnv))
+	if err != nil {
+		logger.Error("Open Platform 环境配置错误，使用正式环境", zap.Error(err))
+		env = OpenPlatformEnvLive
+	}
+	WithOpenPlatformEnv(env)(c)
+	if baseURL := os.Getenv(EnvKeyOpenPlatformBaseURL); baseURL != "" {
+		WithBaseURL(baseURL)(c)
+	}
+	logger.Info("Open Platform 环境", zap.String("env", string(env)), zap.String("base_url", c.baseURL))
+}
+
 // Login 登录
 func (c *Client) Login(account, password, vcode, loginType string) (SubAccountInfo, error) {
 	var accountResp SubAccountInfo
@@ -429,6 +464,15 @@ func (c *Client) GetProductDetailList(cookies, shopID, region, listType string)
 	var ProductDetailList []Product
 	var productIDMap sync.Map
 	var wg sync.WaitGroup
+	// 记录第一个失败页面的错误，任一页面失败时不返回不完整的列表
+	var pageErr error
+	var pageErrOnce sync.Once
+	setPageErr := func(page int, err error) error {
+		pageErrOnce.Do(func() {
+			pageErr = fmt.Errorf("page %d: %w", page, err)
+		})
+		return err
+	}
 
 	SPC_CDS := uuid.New().String()
 	cookies += "SPC_CDS=" + SPC_CDS + ";"
@@ -493,7 +537,7 @@ func (c *Client) GetProductDetailList(cookies, shopID, region, listType string)
 						zap.Int("page", currentPage),
 						zap.Error(err),
 					)
-					return err
+					return setPageErr(currentPage, err)
 				}
 				defer resp.Body.Close()
 
@@ -503,9 +547,12 @@ func (c *Client) GetProductDetailList(cookies, shopID, region, listType string)
 						zap.Int("page", currentPage),
 						zap.Error(err),
 					)
-					return err
+					return setPageErr(currentPage, err)
 				}
 				logger.Info("Body", zap.String("body:", string(body)))
+				if resp.StatusCode != http.StatusOK {
+					return setPageErr(currentPage, fmt.Errorf("status code: %d", resp.StatusCode))
+				}
 
 				var pageResp ProductListResponse
 				if err := json.Unmarshal(body, &pageResp); err != nil {
@@ -513,7 +560,10 @@ func (c *Client) GetProductDetailList(cookies, shopID, region, listType string)
 						zap.Int("page", currentPage),
 						zap.Error(err),
 					)
-					return err
+					return setPageErr(currentPage, err)
+				}
+				if pageResp.Code != ResponseCodeSuccess {
+					return setPageErr(currentPage, fmt.Errorf("code=%d, message=%s", pageResp.Code, pageResp.Message))
 				}
 
 				// 处理商品数据
@@ -540,6 +590,9 @@ func (c *Client) GetProductDetailList(cookies, shopID, region, listType string)
 
 	// 等待所有任务完成
 	wg.Wait()
+	if pageErr != nil {
+		return nil, fmt.Errorf("获取商品列表不完整: %w", pageErr)
+	}
 
 	// 收集结果
 	productIDMap.Range(func(key, value interface{}) bool {
@@ -553,8 +606,55 @@ func (c *Client) GetProductDetailList(cookies, shopID, region, listType string)
 // GetProductListWithDayToShip 获取带出货时间的商品列表
 func (c *Client) GetProductListWithDayToShip(cookies, shopID, region, listType string, dayToShip int) ([]Product, error) {
 	var ProductDetailList []Product
-	var productIDMap sync.Map
 
+	productDetails, err := c.listProductDetails(cookies, shopID, region, listType, func(product ProductDetail) bool {
+		return product.DaysToShip != dayToShip
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 收集结果
+	for _, productDetail := range productDetails {
+		product := Product{
+			ID:        productDetail.ID,
+			ModelList: productDetail.ModelList,
+		}
+		ProductDetailList = append(ProductDetailList, product)
+	}
+
+	return ProductDetailList, nil
+}
+
+// GetProductDetailListWithDayToShip 获取商品的出货天数与预售信息
+func (c *Client) GetProductDetailListWithDayToShip(cookies, shopID, region, listType string) ([]ProductDetail, error) {
+	return c.listProductDetails(cookies, shopID, region, listType, nil)
+}
+
+// listProductDetails 按游标翻页获取商品详情，keep 为空时保留全部商品，任一页面失败时返回错误
+func (c *Client) listProductDetails(cookies, shopID, region, listType string, keep func(ProductDetail) bool) ([]ProductDetail, error) {
+	var productDetailList []ProductDetail
+	seen := make(map[int]bool)
+	err := c.RangeProductDetails(context.Background(), cookies, shopID, region, listType, func(page []ProductDetail) bool {
+		for _, product := range page {
+			if seen[product.ID] || (keep != nil && !keep(product)) {
+				continue
+			}
+			seen[product.ID] = true
+			productDetailList = append(productDetailList, product)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return productDetailList, nil
+}
+
+// RangeProductDetails 按游标逐页获取商品详情(含出货天数与预售信息)并交给 fn，不在内存中保留已处理的页面
+// fn 返回 false 时停止翻页，任一页面失败时返回错误
+func (c *Client) RangeProductDetails(ctx context.Context, cookies, shopID, region, listType string,
+	fn func([]ProductDetail) bool) error {
 	SPC_CDS := uuid.New().String()
 	cookies += "SPC_CDS=" + SPC_CDS + ";"
 
@@ -576,22 +676,22 @@ func (c *Client) GetProductListWithDayToShip(cookies, shopID, region, listType s
 	firstPageParams.Set("page_number", "1")
 
 	APIProductList := APIPathProductDetailList + "?" + firstPageParams.Encode()
-	resp, err := c.doRequest(HTTPMethodGet, APIProductList, nil, cookies)
+	resp, err := c.doRequestWithContext(ctx, HTTPMethodGet, APIProductList, nil, cookies)
 	if err != nil {
-		return nil, fmt.Errorf("get first page failed: %w", err)
+		return fmt.Errorf("get first page failed: %w", err)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
-		return nil, fmt.Errorf("read first page response failed: %w", err)
+		return fmt.Errorf("read first page response failed: %w", err)
 	}
 	logger.Info("Body", zap.String("body:", string(body)))
 
 	var firstPageResp ProductDetailListResponse
 	err = json.Unmarshal(body, &firstPageResp)
 	if err != nil {
-		return nil, fmt.Errorf("unmarshal first page response failed: %w", err)
+		return fmt.Errorf("unmarshal first page response failed: %w", err)
 	}
 
 	pageSize := 50
@@ -599,7 +699,6 @@ func (c *Client) GetProductListWithDayToShip(cookies, shopID, region, listType s
 
 	logger.Info("获取页面", zap.Int("总计页:", totalPages),
 		zap.Int("总计:", firstPageResp.Data.PageInfo.Total))
-	// 使用工作池处理剩余页面
 
 	currentCursor := firstPageResp.Data.PageInfo.Cursor
 	for pageNumber := 0; pageNumber <= totalPages; pageNumber++ {
@@ -608,176 +707,119 @@ func (c *Client) GetProductListWithDayToShip(cookies, shopID, region, listType s
 		params.Set("cursor", currentCursor)
 		apiURL := APIPathProductDetailList + "?" + params.Encode()
 
-		resp, err := c.doRequest(HTTPMethodGet, apiURL, nil, cookies)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := c.doRequestWithContext(ctx, HTTPMethodGet, apiURL, nil, cookies)
 		if err != nil {
-			logger.Error("获取商品列表失败",
-				zap.Int("page", currentPage),
-				zap.Error(err),
-			)
-			continue
+			return fmt.Errorf("获取商品列表不完整: page %d: %w", currentPage, err)
 		}
-		defer resp.Body.Close()
 
 		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
-			logger.Error("读取响应失败",
-				zap.Int("page", currentPage),
-				zap.Error(err),
-			)
-			continue
+			return fmt.Errorf("获取商品列表不完整: page %d: %w", currentPage, err)
 		}
 		logger.Info("Body", zap.String("body:", string(body)))
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("获取商品列表不完整: page %d: status code: %d", currentPage, resp.StatusCode)
+		}
 
 		var pageResp ProductDetailListResponse
 		if err := json.Unmarshal(body, &pageResp); err != nil {
-			logger.Error("解析响应失败",
-				zap.Int("page", currentPage),
-				zap.Error(err),
-			)
-			continue
+			return fmt.Errorf("获取商品列表不完整: page %d: %w", currentPage, err)
 		}
-
-		// 处理商品数据
-		currentTodoProcessNumber := 0
-		for _, product := range pageResp.Data.List {
-			if product.DaysToShip == dayToShip {
-				continue
-			}
-			currentTodoProcessNumber += 1
-			productIDMap.Store(int64(product.ID), product)
+		if pageResp.Code != ResponseCodeSuccess {
+			return fmt.Errorf("获取商品列表不完整: page %d: code=%d, message=%s",
+				currentPage, pageResp.Code, pageResp.Message)
 		}
+
 		logger.Info("页面处理成功", zap.Int("当前所在页:", pageNumber),
-			zap.Int("当前页面总数:", len(pageResp.Data.List)),
-			zap.Int("需要处理的商品:", currentTodoProcessNumber))
+			zap.Int("当前页面总数:", len(pageResp.Data.List)))
+		if !fn(pageResp.Data.List) {
+			return nil
+		}
 		currentCursor = pageResp.Data.PageInfo.Cursor
 	}
 
-	// 收集结果
-	productIDMap.Range(func(key, value interface{}) bool {
-		productDetail := value.(ProductDetail)
-		product := Product{
-			ID:        productDetail.ID,
-			ModelList: productDetail.ModelList,
-		}
-		ProductDetailList = append(ProductDetailList, product)
-		return true
-	})
-
-	return ProductDetailList, nil
+	return nil
 }
 
-// GetAccessTokenWithAreaTw 获取 TW shopee accessToken
+// GetAccessTokenWithAreaTw 获取 TW shopee accessToken, code 不为空时换取授权, 否则使用 refreshToken 刷新
 func (c *Client) GetAccessTokenWithAreaTw(shopId, code, refreshToken string) (string, string, string, error) {
-	var accessToken, newRefreshToken, path, expireTimeFormatted string
-	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
+	var expireTimeFormatted string
 	shopIdInt, _ := strconv.ParseInt(shopId, 10, 64)
-	PartnerIdInt, _ := strconv.ParseInt(constant.PartnerId, 10, 64)
+	partnerId, _ := c.openAPIPartner()
+	partnerIdInt, _ := strconv.ParseInt(partnerId, 10, 64)
 	req := &GetAccessTokenReq{
 		ShopId:    shopIdInt,
-		PartnerId: PartnerIdInt,
+		PartnerId: partnerIdInt,
 	}
+	endpoint := OpenAPIAccessToken
 	if code != "" {
-		path = APIPathAuthTokenForTw
+		endpoint = OpenAPIAuthToken
 		req.Code = code
 	} else {
-		path = APIPathAccessTokenForTw
 		req.RefreshToken = refreshToken
 	}
-	signature := GetPublicSign(constant.PartnerId, path, timestampStr)
-	APIPathAccessTokenForTw := fmt.Sprintf("%s?partner_id=%s&timestamp=%s&sign=%s",
-		path, constant.PartnerId, timestampStr, signature)
-	resp, err := c.doRequest(HTTPMethodPost, APIPathAccessTokenForTw, req, accessToken)
-	if err != nil {
-		return accessToken, newRefreshToken, expireTimeFormatted, fmt.Errorf("get first page failed: %w", err)
-	}
 
-	body, err := io.ReadAll(resp.Body)
-	resp.Body.Close()
+	body, err := c.DoOpenAPIRequest(context.Background(), OpenAPIRequest{
+		Endpoint: endpoint,
+		Body:     req,
+	})
 	if err != nil {
-		return accessToken, newRefreshToken, expireTimeFormatted, fmt.Errorf("read first page response failed: %w", err)
+		return "", "", expireTimeFormatted, fmt.Errorf("get access_token failed: %w", err)
 	}
-	logger.Info("Body", zap.String("body:", string(body)))
+
+	// token 接口的字段直接位于顶层，没有 response 包装
 	var currentResp TWGetAccessTokenResp
-	err = json.Unmarshal(body, &currentResp)
-	if err != nil {
-		return accessToken, newRefreshToken, expireTimeFormatted, fmt.Errorf("unmarshal first page response failed: %w", err)
+	if err := json.Unmarshal(body, &currentResp); err != nil {
+		return "", "", expireTimeFormatted, NewParsingError("unmarshal access_token response failed", err)
 	}
-	if currentResp.ErrorCode != "" {
-		return accessToken, newRefreshToken, expireTimeFormatted, fmt.Errorf("get access_token error %s", currentResp.Message)
-	}
-
-	accessToken = currentResp.AccessToken
-	newRefreshToken = currentResp.RefreshToken
-	expireInInt := currentResp.ExpireIn
-	// 将 expireInInt 转换为 time.Duration 类型（秒 * time.Second）
-	expireInSecond := time.Duration(expireInInt) * time.Second
-	currentTime := time.Now()
-	// 计算到期时间
-	expireTime := currentTime.Add(expireInSecond)
 
-	// 格式化时间为 "yyyy-MM-dd HH:mm:ss"
-	expireTimeFormatted = expireTime.Format("2006-01-02 15:04:05")
-	return accessToken, newRefreshToken, expireTimeFormatted, nil
+	// 将 expire_in(秒) 换算为到期时间, 使用带时区偏移的 RFC3339 格式, 不依赖进程所在时区
+	expireTime := time.Now().Add(time.Duration(currentResp.ExpireIn) * time.Second)
+	expireTimeFormatted = expireTime.Format(time.RFC3339)
+	return currentResp.AccessToken, currentResp.RefreshToken, expireTimeFormatted, nil
 }
 
 // GetProductListWithAreaTw 获取 tw 商品列表
 func (c *Client) GetProductListWithAreaTw(accessToken, shopId string) ([]int64, error) {
 	var productIDs []int64
 	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
-	signature := GetShopSign(constant.PartnerId,
-		APIPathProductListForTw, timestampStr, accessToken, shopId)
-	// 创建基础参数
-	baseParams := url.Values{
-		"partner_id":       {constant.PartnerId},
-		"access_token":     {accessToken},
+	query := url.Values{
 		"item_status":      {"NORMAL"},
-		"timestamp":        {timestampStr},
-		"shop_id":          {shopId},
 		"page_size":        {constant.DefaultPageSize},
-		"sign":             {signature},
 		"update_time_from": {"1264143919"},
 		"update_time_to":   {timestampStr},
 	}
 
 	// 是否还有下一页
 	hasNextPage := true
-	offset := int64(1)
+	offset := int64(0)
 
 	for hasNextPage {
-		params := copyURLValues(baseParams)
-		offsetStr := strconv.FormatInt(int64(offset), 10)
-		params.Set("offset", offsetStr)
+		params := copyURLValues(query)
+		params.Set("offset", strconv.FormatInt(offset, 10))
 
-		APIProductList := APIPathProductListForTw + "?" + params.Encode()
-		resp, err := c.doRequest(HTTPMethodGet, APIProductList, nil, accessToken)
-		if err != nil {
-			return nil, fmt.Errorf("get first page failed: %w", err)
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("read first page response failed: %w", err)
-		}
-		logger.Info("Body", zap.String("body:", string(body)))
-		var currentResp TWProductListResponse
-		err = json.Unmarshal(body, &currentResp)
+		data, err := DoOpenAPIRequestWithResponse[TWProductListData](c, context.Background(), OpenAPIRequest{
+			Endpoint: OpenAPIGetItemList,
+			Auth:     OpenAPIAuth{AccessToken: accessToken, ShopId: shopId},
+			Query:    params,
+		})
 		if err != nil {
-			hasNextPage = false
-			return nil, fmt.Errorf("unmarshal first page response failed: %w", err)
-		}
-
-		if currentResp.Error != "" {
-			return nil, fmt.Errorf("获取商品列表信息失败: %s", currentResp.Message)
+			return nil, fmt.Errorf("获取商品列表信息失败: %w", err)
 		}
 
-		for _, item := range currentResp.Response.Items {
+		for _, item := range data.Items {
 			productIDs = append(productIDs, item.ItemId)
 		}
 
-		offset = currentResp.Response.NextOffset
-		hasNextPage = currentResp.Response.HasNextPage
-
+		if err := data.checkNextOffset(offset); err != nil {
+			return nil, fmt.Errorf("获取商品列表信息失败: %w", err)
+		}
+		offset = data.NextOffset
+		hasNextPage = data.HasNextPage
 	}
 	logger.Info("商品列表获取完成",
 		zap.Int("total_products", len(productIDs)),
@@ -786,6 +828,14 @@ func (c *Client) GetProductListWithAreaTw(accessToken, shopId string) ([]int64,
 	return productIDs, nil
 }
 
+// checkNextOffset 还有下一页但 next_offset 未前进时继续翻页会一直拉取同一页
+func (d *TWProductListData) checkNextOffset(offset int64) error {
+	if d.HasNextPage && d.NextOffset <= offset {
+		return fmt.Errorf("next_offset 未前进: offset=%d, next_offset=%d", offset, d.NextOffset)
+	}
+	return nil
+}
+
 // UpdateProductInfoWithAreaTwItem 更新商品信息请求
 type UpdateProductInfoWithAreaTwItem struct {
 	DaysToShip int  `json:"days_to_ship"`
@@ -800,51 +850,18 @@ type UpdateProductInfoWithAreaTw struct {
 
 // UpdateProductInfoWithAreaTw 更新 tw 商品
 func (c *Client) UpdateProductInfoWithAreaTw(accessToken, shopId string, itemId int64, item UpdateProductInfoWithAreaTwItem) error {
-	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
-	signature := GetShopSign(constant.PartnerId,
-		APIPathProductUpdateForTw, timestampStr, accessToken, shopId)
-	shopIdInt, _ := strconv.ParseInt(shopId, 10, 64)
-
-	// 创建基础参数
-	baseParams := url.Values{
-		"partner_id":   {constant.PartnerId},
-		"timestamp":    {timestampStr},
-		"access_token": {accessToken},
-		"sign":         {signature},
-	}
-
-	req := UpdateProductInfoWithAreaTw{ItemId: itemId, PreOrder: item}
-	params := copyURLValues(baseParams)
-
-	APIProductUpdate := APIPathProductUpdateForTw + "?" + params.Encode()
-	APIProductUpdate = fmt.Sprintf("%s&%s=%d", APIProductUpdate, "shop_id", shopIdInt)
-	resp, err := c.doRequest(HTTPMethodPost, APIProductUpdate, req, accessToken)
-	if err != nil {
-		return fmt.Errorf("get first page failed: %w", err)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	resp.Body.Close()
-	if err != nil {
-		return fmt.Errorf("read first page response failed: %w", err)
-	}
-	logger.Info("Body", zap.String("body:", string(body)))
-	var currentResp TWProductUpdateResponse
-	err = json.Unmarshal(body, &currentResp)
+	data, err := DoOpenAPIRequestWithResponse[TWProductUpdateData](c, context.Background(), OpenAPIRequest{
+		Endpoint: OpenAPIUpdateItem,
+		Auth:     OpenAPIAuth{AccessToken: accessToken, ShopId: shopId},
+		Body:     UpdateProductInfoWithAreaTw{ItemId: itemId, PreOrder: item},
+	})
 	if err != nil {
-		return fmt.Errorf("unmarshal first page response failed: %w", err)
-	}
-	if currentResp.Error != "" {
-		return fmt.Errorf("更新商品:%d, 失败: %s", itemId, currentResp.Message)
-	}
-	if currentResp.Msg == RateLimitError {
-		return fmt.Errorf(RateLimitError)
+		return fmt.Errorf("更新商品:%d, 失败: %w", itemId, err)
 	}
-	if item.DaysToShip == currentResp.Response.PreOrder.DaysToShip {
+	if item.DaysToShip == data.PreOrder.DaysToShip {
 		logger.Info("商品更新成功", zap.Int64("product_id", itemId))
 	} else {
-		logger.Error("商品更新失败", zap.Int64("product_id", itemId), zap.Any("currentResp", currentResp),
-			zap.Error(err))
+		logger.Error("商品更新失败", zap.Int64("product_id", itemId), zap.Any("currentResp", data))
 	}
 
 	return nil
@@ -853,50 +870,14 @@ func (c *Client) UpdateProductInfoWithAreaTw(accessToken, shopId string, itemId
 // GetProductBaseInfoWithAreaTw 获取商品基础信息，主要是为了获取 出货时间，验证更新结果
 func (c *Client) GetProductBaseInfoWithAreaTw(accessToken, shopId string, itemIdList []int64) ([]ProductBaseInfoWithAreaTwComplate, error) {
 	var productInfos []ProductBaseInfoWithAreaTwComplate
-	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
-	signature := GetShopSign(constant.PartnerId,
-		APIPathGetBaseProductInfo, timestampStr, accessToken, shopId)
-
-	// 将 itemIDs 转换为逗号分隔的字符串
-	itemIDStrs := make([]string, len(itemIdList))
-	for i, id := range itemIdList {
-		itemIDStrs[i] = strconv.FormatInt(id, 10)
-	}
-	itemIDList := strings.Join(itemIDStrs, ",")
-	// 创建基础参数
-	baseParams := url.Values{
-		"partner_id":   {constant.PartnerId},
-		"access_token": {accessToken},
-		"timestamp":    {timestampStr},
-		"shop_id":      {shopId},
-		"sign":         {signature},
-		"item_id_list": {itemIDList},
-	}
-
-	params := copyURLValues(baseParams)
 
-	APIProductList := APIPathGetBaseProductInfo + "?" + params.Encode()
-	resp, err := c.doRequest(HTTPMethodGet, APIProductList, nil, accessToken)
+	itemList, err := c.GetItemBaseInfoListWithAreaTw(context.Background(),
+		OpenAPIAuth{AccessToken: accessToken, ShopId: shopId}, itemIdList)
 	if err != nil {
-		return nil, fmt.Errorf("get first page failed: %w", err)
+		return nil, fmt.Errorf("获取商品列表信息失败: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	resp.Body.Close()
-	if err != nil {
-		return nil, fmt.Errorf("read first page response failed: %w", err)
-	}
-	var currentResp ProductBaseInfoWithAreaTwResp
-	err = json.Unmarshal(body, &currentResp)
-	if err != nil {
-		return nil, fmt.Errorf("unmarshal first page response failed: %w", err)
-	}
-
-	if currentResp.Error != "" {
-		return nil, fmt.Errorf("获取商品列表信息失败: %s", currentResp.Message)
-	}
-
-	for _, item := range currentResp.Response.ItemList {
+	for _, item := range itemList {
 		productInfos = append(productInfos, ProductBaseInfoWithAreaTwComplate{
 			ItemId:     item.ItemId,
 			DaysToShip: item.PreOrder.DaysToShip,
@@ -946,6 +927,23 @@ type BatchUpdateProductInfoResponse struct {
 
 // UpdateProductInfo 更新商品信息
 func (c *Client) UpdateProductInfo(updateProductInfoReq UpdateProductInfoReq) error {
+	return c.updateProductInfo(updateProductInfoReq, nil)
+}
+
+// UpdateProductModelDaysToShip 按规格修改单个商品的出货天数，发送前校验规格是否属于该商品
+func (c *Client) UpdateProductModelDaysToShip(updateProductInfoReq UpdateProductInfoReq, changes []ModelDaysToShip) error {
+	if len(changes) == 0 {
+		return NewValidationError(fmt.Sprintf("商品 %d 没有需要修改的规格", updateProductInfoReq.ProductId))
+	}
+	return c.updateProductInfo(updateProductInfoReq, changes)
+}
+
+func (c *Client) updateProductInfo(updateProductInfoReq UpdateProductInfoReq, modelDaysToShip []ModelDaysToShip) error {
+	req, err := c.buildUpdateProductInfoRequest(updateProductInfoReq, modelDaysToShip)
+	if err != nil {
+		return err
+	}
+
 	SPC_CDS := uuid.New().String()
 	updateProductInfoReq.Cookies += "SPC_CDS=" + SPC_CDS + ";"
 
@@ -955,27 +953,6 @@ func (c *Client) UpdateProductInfo(updateProductInfoReq UpdateProductInfoReq) er
 	updateProductInfoParams.Set("cnsc_shop_id", updateProductInfoReq.ShopID)
 	updateProductInfoParams.Set("cbsc_shop_region", updateProductInfoReq.Region)
 
-	req := &UpdateProductInfoRequest{
-		ProductID: updateProductInfoReq.ProductId,
-		ProductInfo: ProductInfo{
-			EnableModelLevelDts: false,
-		},
-		IsDraft: false,
-	}
-
-	if updateProductInfoReq.DaysToShip != 0 {
-		preOrderInfo := PreOrderInfo{
-			PreOrder:   true,
-			DaysToShip: updateProductInfoReq.DaysToShip,
-		}
-		if updateProductInfoReq.DaysToShip == 2 {
-			preOrderInfo.PreOrder = false
-		}
-		req.ProductInfo.PreOrderInfo = preOrderInfo
-	} else {
-		req.ProductInfo.Unlisted = updateProductInfoReq.ProductStatus.Unlisted
-	}
-
 	APIUpdateProductInfo := APIPathUpdateProductInfo + "?" + updateProductInfoParams.Encode()
 	resp, err := c.doRequest(HTTPMethodPost, APIUpdateProductInfo, req, updateProductInfoReq.Cookies)
 	if err != nil {
@@ -1006,17 +983,6 @@ func (c *Client) UpdateProductInfo(updateProductInfoReq UpdateProductInfoReq) er
 // BatchUpdateProductInfoWithV3 使用 V3 接口批量更新商品信息
 func (c *Client) BatchUpdateProductInfoWithV3(updateProductInfoReq UpdateProductInfoReq,
 	shopIdList []int64, source, action string) ([]BatchUpdateProductInfoRespItem, error) {
-	SPC_CDS := uuid.New().String()
-	updateProductInfoReq.Cookies += "SPC_CDS=" + SPC_CDS + ";"
-
-	updateProductInfoParams := url.Values{}
-	updateProductInfoParams.Set("SPC_CDS", SPC_CDS)
-	updateProductInfoParams.Set("SPC_CDS_VER", "2")
-	updateProductInfoParams.Set("cnsc_shop_id", updateProductInfoReq.ShopID)
-	updateProductInfoParams.Set("cbsc_shop_region", updateProductInfoReq.Region)
-	updateProductInfoParams.Set("version", "3.1.0")
-	updateProductInfoParams.Set("source", source)
-
 	batchUpdateProductInfoReq := make([]*BatchUpdateProductInfoItem, 0, len(shopIdList))
 	for _, shopId := range shopIdList {
 		currentReq := &BatchUpdateProductInfoItem{
@@ -1040,78 +1006,78 @@ func (c *Client) BatchUpdateProductInfoWithV3(updateProductInfoReq UpdateProduct
 
 	}
 
-	APIUpdateProductInfo := APIPathBatchUpdateProductInfo + "?" + updateProductInfoParams.Encode()
-	resp, err := c.doRequest(HTTPMethodPost, APIUpdateProductInfo, batchUpdateProductInfoReq, updateProductInfoReq.Cookies)
-	if err != nil {
-		return nil, fmt.Errorf("update product info failed, request error: %w", err)
-	}
+	return c.postBatchUpdateProductInfo(updateProductInfoReq, source, batchUpdateProductInfoReq)
+}
 
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response body failed: %w", err)
-	}
-	logger.Info("Body", zap.String("body:", string(body)))
-	if resp.StatusCode == RateLimitCode {
-		return nil, fmt.Errorf(RateLimitError)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("update product info failed, status code: %d, message: %s", resp.StatusCode, string(body))
-	}
-	var updateProductInfoResp BatchUpdateProductInfoResponse
-	err = json.Unmarshal(body, &updateProductInfoResp)
-	if err != nil {
-		return nil, fmt.Errorf("unmarshal update product info response failed: %w", err)
-	}
-	if updateProductInfoResp.Code != ResponseCodeSuccess {
-		return updateProductInfoResp.Data.Result, fmt.Errorf("update product info failed, message: %s", updateProductInfoResp.UserMessage)
+// NewDaysToShipItem 构造修改出货天数的批量更新项，2 天表示非预售
+// 批量接口总会带上 unlisted，必须传入商品当前的上下架状态，否则会改变商品的上下架
+func NewDaysToShipItem(productId int64, daysToShip int, unlisted bool) BatchUpdateProductInfoItem {
+	return BatchUpdateProductInfoItem{
+		ID:         productId,
+		DaysToShip: daysToShip,
+		PreOrder:   daysToShip != 2,
+		Unlisted:   unlisted,
 	}
+}
 
-	return updateProductInfoResp.Data.Result, nil
+// BatchUpdateProductItems 使用 V3 批量接口提交逐个商品的修改，每个商品的 Unlisted 需为期望的上下架状态
+func (c *Client) BatchUpdateProductItems(req UpdateProductInfoReq, items []BatchUpdateProductInfoItem,
+	source string) ([]BatchUpdateProductInfoRespItem, error) {
+	batch := make([]*BatchUpdateProductInfoItem, 0, len(items))
+	for i := range items {
+		batch = append(batch, &items[i])
+	}
+	return c.postBatchUpdateProductInfo(req, source, batch)
 }
 
-// BatchUpdateProductInfoWithFile 使用 excel 接口批量更新商品信息
-func (c *Client) BatchUpdateProductInfoWithFile(updateProductInfoReq UpdateProductInfoReq, filename string) error {
+// postBatchUpdateProductInfo 提交 V3 批量更新请求，items 中每个商品可以携带不同的修改
+func (c *Client) postBatchUpdateProductInfo(updateProductInfoReq UpdateProductInfoReq, source string,
+	batchUpdateProductInfoReq []*BatchUpdateProductInfoItem) ([]BatchUpdateProductInfoRespItem, error) {
 	SPC_CDS := uuid.New().String()
-	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
+	updateProductInfoReq.Cookies += "SPC_CDS=" + SPC_CDS + ";"
 
 	updateProductInfoParams := url.Values{}
 	updateProductInfoParams.Set("SPC_CDS", SPC_CDS)
 	updateProductInfoParams.Set("SPC_CDS_VER", "2")
 	updateProductInfoParams.Set("cnsc_shop_id", updateProductInfoReq.ShopID)
 	updateProductInfoParams.Set("cbsc_shop_region", updateProductInfoReq.Region)
-	updateProductInfoParams.Set("timestamp", timestampStr)
+	updateProductInfoParams.Set("version", "3.1.0")
+	updateProductInfoParams.Set("source", source)
 
-	APIUpdateProductInfo := APIPathBatchUpdateProductInfoWithFile + "?" + updateProductInfoParams.Encode()
-	resp, err := c.doRequestWithFile(HTTPMethodPost, APIUpdateProductInfo, updateProductInfoReq.Cookies, "file", filename)
+	APIUpdateProductInfo := APIPathBatchUpdateProductInfo + "?" + updateProductInfoParams.Encode()
+	resp, err := c.doRequest(HTTPMethodPost, APIUpdateProductInfo, batchUpdateProductInfoReq, updateProductInfoReq.Cookies)
 	if err != nil {
-		return fmt.Errorf("update product info failed, request error: %w", err)
+		return nil, fmt.Errorf("update product info failed, request error: %w", err)
 	}
 
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read response body failed: %w", err)
+		return nil, fmt.Errorf("read response body failed: %w", err)
 	}
 	logger.Info("Body", zap.String("body:", string(body)))
 	if resp.StatusCode == RateLimitCode {
-		return fmt.Errorf(RateLimitError)
+		return nil, NewRateLimitError(RateLimitError)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("update product info failed, status code: %d, message: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("update product info failed, status code: %d, message: %s", resp.StatusCode, string(body))
 	}
 	var updateProductInfoResp BatchUpdateProductInfoResponse
 	err = json.Unmarshal(body, &updateProductInfoResp)
 	if err != nil {
-		return fmt.Errorf("unmarshal update product info response failed: %w", err)
+		return nil, fmt.Errorf("unmarshal update product info response failed: %w", err)
 	}
-	if updateProductInfoResp.Code != ResponseCodeSuccess &&
-		updateProductInfoResp.Code != ProcessCode {
-		return fmt.Errorf("update product info failed, message: %+v\b", updateProductInfoResp)
+	if updateProductInfoResp.Code != ResponseCodeSuccess {
+		return updateProductInfoResp.Data.Result, fmt.Errorf("update product info failed, message: %s", updateProductInfoResp.UserMessage)
 	}
 
-	return nil
+	return updateProductInfoResp.Data.Result, nil
+}
 
+// BatchUpdateProductInfoWithFile 使用 excel 接口批量更新商品信息
+func (c *Client) BatchUpdateProductInfoWithFile(updateProductInfoReq UpdateProductInfoReq, filename string) error {
+	_, err := c.UploadMassEditFile(updateProductInfoReq, filename)
+	return err
 }
 
 // SwitchMerchantShop 切换店铺
@@ -1189,68 +1155,38 @@ func (c *Client) GetOrSetShop(cookies string) error {
 
 }
 
-// GetInactiveProducts 获取不活跃的商品信息
+// GetInactiveProducts 获取不活跃的商品信息(点赞、销量、浏览全为 0，越旧越靠前)
 func (c *Client) GetInactiveProducts(cookies, shopId, region string, batch int) ([]int64, error) {
 	var productIdList []int64
-	productList, err := c.GetProductDetailList(cookies, shopId, region, ListTypeLive)
+	candidates, err := c.GetInactiveProductsWithPolicy(cookies, shopId, region, DefaultInactivityPolicy(), batch)
 	if err != nil {
-		logger.Info("获取商品详细信息失败", zap.Any("err", err))
 		return productIdList, err
 	}
-
-	logger.Info("获取商品详细信息", zap.Any("total product", len(productList)))
-
-	var inActiveProductList []Product
-
-	// 首先获取：喜欢，销量，点击 为 0 的商品信息
-	for _, product := range productList {
-		if product.Statistics.IsActiveProduct() {
-			inActiveProductList = append(inActiveProductList, product)
-		}
+	for _, candidate := range candidates {
+		productIdList = append(productIdList, candidate.ProductId)
 	}
+	return productIdList, nil
+}
 
-	// 根据创建时间进行排序，获取前 50 个商品Id
-	// 根据创建时间进行排序（升序：越旧的在前）
-	sort.Slice(inActiveProductList, func(i, j int) bool {
-		return inActiveProductList[i].CreateTime < inActiveProductList[j].CreateTime
-	})
-
-	// 取前 50 个商品 ID
-	limit := batch
-	if len(inActiveProductList) < limit {
-		limit = len(inActiveProductList)
+// GetInactiveProductsWithPolicy 按不活跃策略筛选在售商品，返回排序后的前 batch 个候选及原因
+// batch <= 0 时不返回商品
+func (c *Client) GetInactiveProductsWithPolicy(cookies, shopId, region string, policy InactivityPolicy, batch int) ([]InactiveCandidate, error) {
+	if batch <= 0 {
+		return nil, nil
 	}
-	for i := 0; i < limit; i++ {
-		productIdList = append(productIdList, int64(inActiveProductList[i].ID))
+	productList, err := c.GetProductDetailList(cookies, shopId, region, ListTypeLive)
+	if err != nil {
+		logger.Info("获取商品详细信息失败", zap.Any("err", err))
+		return nil, err
 	}
 
-	return productIdList, nil
-}
+	logger.Info("获取商品详细信息", zap.Any("total product", len(productList)))
 
-// ListedOrUnlistedProducts 上下架商品
-func (c *Client) ListedOrUnlistedProducts(shopId, cookies, region string, listStatus bool, productIds []int64) int64 {
-	var successfulNumber int64
-	for _, productId := range productIds {
-		time.Sleep(time.Second * 2)
-		currentUpdateProductInfoReq := UpdateProductInfoReq{
-			ProductId: productId,
-			ShopID:    shopId,
-			Region:    region,
-			Cookies:   cookies,
-			ProductStatus: ProductStatusInfo{
-				Unlisted: listStatus,
-			},
-		}
-		if err := c.UpdateProductInfo(currentUpdateProductInfoReq); err != nil {
-			logger.Info("商品更新失败", zap.String("shopId", shopId),
-				zap.Int64("productId", productId), zap.Any("error", err))
-			continue
-		}
-		successfulNumber += 1
+	candidates := policy.Evaluate(productList, time.Now())
+	if len(candidates) > batch {
+		candidates = candidates[:batch]
 	}
-
-	logger.Info("更新商品完成", zap.Int64("总计更新", successfulNumber))
-	return successfulNumber
+	return candidates, nil
 }
 
 // get_discount_list
@@ -1391,10 +1327,18 @@ func (c *Client) UpdateDiscountItem(cookies, shopId, region string, req UpdateDi
 	return data.SuccessCount, nil
 }
 
-// DeleteProducts 删除商品
+// DeleteProducts 删除商品，返回删除成功的数量
 func (c *Client) DeleteProducts(shopId, cookies, region string, productIds []int64) (int64, error) {
+	failed, err := c.DeleteProductsWithResult(shopId, cookies, region, productIds)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(productIds) - len(failed)), nil
+}
+
+// DeleteProductsWithResult 删除商品并返回删除失败的商品及原因，err 不为空时整批未删除
+func (c *Client) DeleteProductsWithResult(shopId, cookies, region string, productIds []int64) (map[int64]string, error) {
 	SPC_CDS := uuid.New().String()
-	var successfulNumber int64
 
 	var deleteProductReq DeleteProductReq
 	deleteProductReq.ProductIdList = productIds
@@ -1409,30 +1353,38 @@ func (c *Client) DeleteProducts(shopId, cookies, region string, productIds []int
 	APIUpdateProductInfo := APIPathDeleteProduct + "?" + deleteProductParams.Encode()
 	resp, err := c.doRequest(HTTPMethodPost, APIUpdateProductInfo, deleteProductReq, cookies)
 	if err != nil {
-		return successfulNumber, fmt.Errorf("delete product info failed, request error: %w", err)
+		return nil, fmt.Errorf("delete product info failed, request error: %w", err)
 	}
 
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return successfulNumber, fmt.Errorf("read response body failed: %w", err)
+		return nil, fmt.Errorf("read response body failed: %w", err)
 	}
 	logger.Info("Body", zap.String("body:", string(body)))
+	if resp.StatusCode == RateLimitCode {
+		return nil, fmt.Errorf(RateLimitError)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return successfulNumber, fmt.Errorf("delete product info failed, status code: %d, message: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("delete product info failed, status code: %d, message: %s", resp.StatusCode, string(body))
 	}
-	var updateProductInfoResp UpdateProductInfoResponse
-	err = json.Unmarshal(body, &updateProductInfoResp)
+	var deleteProductResp BatchUpdateProductInfoResponse
+	err = json.Unmarshal(body, &deleteProductResp)
 	if err != nil {
-		return successfulNumber, fmt.Errorf("unmarshal update product info response failed: %w", err)
+		return nil, fmt.Errorf("unmarshal delete product response failed: %w", err)
 	}
-	if updateProductInfoResp.Code != ResponseCodeSuccess {
-		return successfulNumber, fmt.Errorf("delete product info failed, message: %s", updateProductInfoResp.UserMessage)
+	if deleteProductResp.Code != ResponseCodeSuccess {
+		return nil, fmt.Errorf("delete product info failed, message: %s", deleteProductResp.UserMessage)
 	}
 
-	successfulNumber = int64(len(productIds))
-
-	return successfulNumber, nil
+	// 接口只返回处理过的商品，未出现的商品视为删除成功
+	failed := make(map[int64]string)
+	for _, item := range deleteProductResp.Data.Result {
+		if item.Code != ResponseCodeSuccess {
+			failed[item.ID] = batchItemReason(item)
+		}
+	}
+	return failed, nil
 }
 
 func (c *Client) doRequestWithProxy(method, path string, reqBody interface{}, cookies string) (*http.Response, error) {
@@ -1630,6 +1582,27 @@ func (c *Client) doRequestWithContext(ctx context.Context, method, path string,
 	return resp, nil
 }
 
+// doCommonRequest 发送请求并按 CommonResponse 解析 data
+func doCommonRequest[T any](c *Client, ctx context.Context, method, apiURL string, reqBody interface{}, cookies string) (*T, error) {
+	resp, err := c.doRequestWithContext(ctx, method, apiURL, reqBody, cookies)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+	logger.Info("Body", zap.String("body:", string(body)))
+	if resp.StatusCode == RateLimitCode {
+		return nil, fmt.Errorf(RateLimitError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d, message: %s", resp.StatusCode, string(body))
+	}
+	return ParseCommonResponse[T](body)
+}
+
 func (c *Client) doRequestWithFile(method, path, cookies, fileFieldName, filePath string) (*http.Response, error) {
 	url := c.baseURL + path
 
@@ -179,7 +179,7 @@ func TestClient_GetDiscountList(t *testing.T) {
 	shopId := "1332278997"
 	cookieStr := "SPC_CNSC_SESSION=f42893f98d19410156a15246b727dd03_2_2339367_g5Nw+/j4z0csq0Ef5PVP7/6DoKL56Fsmfqh/4WyMUak3RNv64Zb9qz5+dZhSyOqsQpUGeKLZBB5EmGrX5I1Y9GFEZcbB6kErRcf3oCspw12zftQzrlZY2ycBFJtCHJVUueUTAf0HNO4S35srfoIrcgWPgYodg4BZEGaISuAt0WorjxhzcAdVTDBUKt24kJsIaFiQimKVSJowwF5wUIWckls9kgTVwsavI5oqENE9TAjk=;"
 
-	discounts, err := client.GetDiscountList(cookieStr, shopId, "sg")
+	discounts, err := client.GetDiscountList(cookieStr, shopId, "sg", 0)
 	if err != nil {
 		log.Fatal(err)
 	}
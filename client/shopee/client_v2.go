@@ -173,6 +173,11 @@ func (c *Client) GetProductListV2(cookies, shopID, region, listType string) ([]i
 
 // 重构后的更新商品信息方法示例
 func (c *Client) UpdateProductInfoV2(updateReq UpdateProductInfoReq) error {
+	req, err := c.buildUpdateProductInfoRequest(updateReq, nil)
+	if err != nil {
+		return err
+	}
+
 	SPC_CDS := uuid.New().String()
 	updateReq.Cookies += "SPC_CDS=" + SPC_CDS + ";"
 
@@ -183,32 +188,11 @@ func (c *Client) UpdateProductInfoV2(updateReq UpdateProductInfoReq) error {
 		"cbsc_shop_region": {updateReq.Region},
 	}
 
-	req := &UpdateProductInfoRequest{
-		ProductID: updateReq.ProductId,
-		ProductInfo: ProductInfo{
-			EnableModelLevelDts: false,
-		},
-		IsDraft: false,
-	}
-
-	if updateReq.DaysToShip != 0 {
-		preOrderInfo := PreOrderInfo{
-			PreOrder:   true,
-			DaysToShip: updateReq.DaysToShip,
-		}
-		if updateReq.DaysToShip == 2 {
-			preOrderInfo.PreOrder = false
-		}
-		req.ProductInfo.PreOrderInfo = preOrderInfo
-	} else {
-		req.ProductInfo.Unlisted = updateReq.ProductStatus.Unlisted
-	}
-
 	rm := NewRequestManager(c)
 	apiURL := APIPathUpdateProductInfo + "?" + params.Encode()
 
 	// 使用新的请求管理器
-	_, err := DoRequestWithCommonResponse[UpdateProductInfoData](
+	_, err = DoRequestWithCommonResponse[UpdateProductInfoData](
 		rm,
 		context.Background(),
 		HTTPMethodPost,
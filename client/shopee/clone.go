@@ -0,0 +1,199 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// CloneProductRequest 克隆商品请求，目标店铺需与源店铺属于同一商户
+type CloneProductRequest struct {
+	Cookies      string
+	SourceShopId string
+	SourceRegion string
+	ProductId    int64
+	TargetShopId string
+	TargetRegion string
+
+	CategoryPath      []int64         // 目标类目，为空时沿用源商品类目，跨区域时必填
+	PriceRate         float64         // 价格倍率(含汇率)，源与目标货币不同时必填
+	LogisticsChannels map[int64]int64 // 源物流渠道到目标渠道的映射，跨区域时未映射的渠道不会带过去
+	Stock             *int            // 所有规格使用的库存，为空时沿用源库存
+	Unlisted          bool            // 创建后保持下架
+}
+
+// CloneDroppedField 未能带到目标店铺的字段
+type CloneDroppedField struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// CloneProductReport 克隆结果
+type CloneProductReport struct {
+	SourceProductId int64               `json:"source_product_id"`
+	TargetShopId    string              `json:"target_shop_id"`
+	TargetRegion    string              `json:"target_region"`
+	TargetProductId int64               `json:"target_product_id"`
+	Dropped         []CloneDroppedField `json:"dropped"`
+}
+
+// CreateProduct 在店铺中创建商品，返回新商品 id
+func (c *Client) CreateProduct(ctx context.Context, cookies, shopId, region string, product ProductDefinition) (int64, error) {
+	param := CommomParam{shopId, region}
+	apiURL := APIPathCreateProduct + "?" + param.ToFormValues().Encode()
+	data, err := doCommonRequest[CreateProductData](c, ctx, HTTPMethodPost, apiURL, &createProductRequest{ProductInfo: product}, cookies)
+	if err != nil {
+		return 0, fmt.Errorf("create product failed: %w", err)
+	}
+	return data.ProductID, nil
+}
+
+// CloneProduct 读取源店铺商品的完整定义，替换店铺相关的字段后在目标店铺创建
+// 目标店铺与源店铺不同时先切换到目标店铺，完成后切换回源店铺
+func (c *Client) CloneProduct(ctx context.Context, req CloneProductRequest) (*CloneProductReport, error) {
+	if req.Cookies == "" || req.SourceShopId == "" || req.SourceRegion == "" ||
+		req.TargetShopId == "" || req.TargetRegion == "" || req.ProductId == 0 {
+		return nil, NewValidationError("源店铺、目标店铺与商品 id 不能为空")
+	}
+	if err := c.checkMerchantShop(req.Cookies, req.TargetShopId, req.TargetRegion); err != nil {
+		return nil, err
+	}
+
+	source, err := c.GetProductDefinition(ctx, req.Cookies, req.SourceShopId, req.SourceRegion, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	product, dropped, err := remapProductForClone(*source, req)
+	if err != nil {
+		return nil, err
+	}
+	report := &CloneProductReport{
+		SourceProductId: req.ProductId,
+		TargetShopId:    req.TargetShopId,
+		TargetRegion:    req.TargetRegion,
+		Dropped:         dropped,
+	}
+
+	if req.TargetShopId != req.SourceShopId {
+		if err := c.SwitchMerchantShop(req.Cookies, req.TargetRegion, req.TargetShopId); err != nil {
+			return nil, fmt.Errorf("切换到目标店铺失败: %w", err)
+		}
+		defer func() {
+			if err := c.SwitchMerchantShop(req.Cookies, req.SourceRegion, req.SourceShopId); err != nil {
+				logger.Warn("切换回源店铺失败", zap.String("shop_id", req.SourceShopId), zap.Error(err))
+			}
+		}()
+	}
+	report.TargetProductId, err = c.CreateProduct(ctx, req.Cookies, req.TargetShopId, req.TargetRegion, product)
+	if err != nil {
+		return report, err
+	}
+
+	logger.Info("商品克隆完成", zap.Int64("source_product_id", req.ProductId), zap.String("target_shop_id", req.TargetShopId),
+		zap.Int64("target_product_id", report.TargetProductId), zap.Int("dropped", len(report.Dropped)))
+	return report, nil
+}
+
+// checkMerchantShop 确认目标店铺在当前登录商户下
+func (c *Client) checkMerchantShop(cookies, shopId, region string) error {
+	shops, err := c.GetMerchantShopList(cookies)
+	if err != nil {
+		return err
+	}
+	for _, shop := range shops {
+		if strconv.FormatInt(shop.ShopID, 10) == shopId && strings.EqualFold(shop.Region, region) {
+			return nil
+		}
+	}
+	return NewValidationError(fmt.Sprintf("目标店铺 %s(%s) 不在当前商户下", shopId, region))
+}
+
+// remapProductForClone 生成在目标店铺创建用的商品定义，返回无法带过去的字段
+func remapProductForClone(product ProductDefinition, req CloneProductRequest) (ProductDefinition, []CloneDroppedField, error) {
+	var dropped []CloneDroppedField
+	sourceCurrency, err := CurrencyOfRegion(req.SourceRegion)
+	if err != nil {
+		return product, nil, err
+	}
+	targetCurrency, err := CurrencyOfRegion(req.TargetRegion)
+	if err != nil {
+		return product, nil, err
+	}
+	rate := req.PriceRate
+	if rate <= 0 {
+		if sourceCurrency.Code != targetCurrency.Code {
+			return product, nil, NewValidationError(fmt.Sprintf("%s 到 %s 需要指定价格倍率", sourceCurrency.Code, targetCurrency.Code))
+		}
+		rate = 1
+	}
+
+	// 类目 id 按区域区分，跨区域时必须指定目标类目
+	sameRegion := strings.EqualFold(req.SourceRegion, req.TargetRegion)
+	if !sameRegion && len(req.CategoryPath) == 0 {
+		return product, nil, NewValidationError("跨区域克隆需要指定目标类目")
+	}
+	// 属性、品牌与尺码表依附于源类目与源区域，类目或区域变化时不带过去
+	if !sameRegion || (len(req.CategoryPath) > 0 && !reflect.DeepEqual(req.CategoryPath, product.CategoryPath)) {
+		if len(product.Attributes) > 0 {
+			dropped = append(dropped, CloneDroppedField{Field: "attributes", Reason: "属性属于源类目，需在目标类目下重新填写"})
+			product.Attributes = nil
+		}
+		if product.BrandInfo != (ProductBrandInfo{}) {
+			dropped = append(dropped, CloneDroppedField{Field: "brand_info", Reason: "品牌属于源类目，需在目标类目下重新选择"})
+			product.BrandInfo = ProductBrandInfo{}
+		}
+		if product.SizeChart != "" {
+			dropped = append(dropped, CloneDroppedField{Field: "size_chart", Reason: "尺码表只能在源店铺的源类目下使用"})
+			product.SizeChart = ""
+		}
+	}
+
+	product.ID = 0
+	product.Unlisted = req.Unlisted
+	if len(req.CategoryPath) > 0 {
+		product.CategoryPath = req.CategoryPath
+	}
+	if len(product.VideoList) > 0 {
+		dropped = append(dropped, CloneDroppedField{Field: "video_list", Reason: "视频只能在上传的店铺使用"})
+		product.VideoList = nil
+	}
+
+	models := make([]ProductModelInfo, 0, len(product.ModelList))
+	for _, m := range product.ModelList {
+		m.ID = 0
+		price, err := targetCurrency.ToMinorUnits(sourceCurrency.FromMinorUnits(m.InputNormalPrice) * rate)
+		if err != nil {
+			return product, nil, fmt.Errorf("规格 %s 价格换算失败: %w", m.Sku, err)
+		}
+		m.InputNormalPrice = price
+		if req.Stock != nil {
+			m.SellerStock = *req.Stock
+		}
+		models = append(models, m)
+	}
+	product.ModelList = models
+
+	channels := make([]LogisticsChannel, 0, len(product.LogisticsChannels))
+	for _, channel := range product.LogisticsChannels {
+		if target, ok := req.LogisticsChannels[channel.ChannelID]; ok {
+			channel.ChannelID = target
+		} else if !sameRegion {
+			if channel.Enabled {
+				dropped = append(dropped, CloneDroppedField{
+					Field:  fmt.Sprintf("logistics_channels[%d]", channel.ChannelID),
+					Reason: "目标区域没有对应的物流渠道",
+				})
+			}
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	product.LogisticsChannels = channels
+	return product, dropped, nil
+}
@@ -0,0 +1,112 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCloneProduct(t *testing.T) {
+	var switched []string
+	var created createProductRequest
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPathGetMerchantShopList:
+			io.WriteString(w, `{"code":0,"data":{"shops":[{"region":"SG","shop_id":100},{"region":"MY","shop_id":200}]}}`)
+		case APIPathSwitchMerchantShop:
+			switched = append(switched, r.URL.Query().Get("cnsc_shop_id"))
+			io.WriteString(w, `{"code":0,"message":"success"}`)
+		case APIPathGetProductInfo:
+			if r.URL.Query().Get("product_id") != "1" {
+				t.Errorf("unexpected product_id %s", r.URL.Query().Get("product_id"))
+			}
+			io.WriteString(w, `{"code":0,"data":{"product_info":{"id":1,"name":"T恤","category_path":[100,101],
+				"attributes":[{"attribute_id":5,"attribute_value_id":6}],"brand_info":{"brand_id":7},"size_chart":"chart1",
+				"images":["img1"],"video_list":[{"video_id":"v1"}],
+				"std_tier_variation_list":[{"name":"颜色","options":[{"option":"红"}]}],
+				"model_list":[{"id":11,"tier_index":[0],"sku":"R","input_normal_price":1000,"seller_stock":5}],
+				"logistics_channels":[{"channel_id":1,"enabled":true},{"channel_id":2,"enabled":true}],
+				"pre_order_info":{"pre_order":true,"days_to_ship":7}}}}`)
+		case APIPathCreateProduct:
+			json.NewDecoder(r.Body).Decode(&created)
+			io.WriteString(w, `{"code":0,"data":{"product_id":9}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	req := CloneProductRequest{
+		Cookies:           "cookie",
+		SourceShopId:      "100",
+		SourceRegion:      "SG",
+		ProductId:         1,
+		TargetShopId:      "200",
+		TargetRegion:      "MY",
+		CategoryPath:      []int64{200, 201},
+		PriceRate:         3.5,
+		LogisticsChannels: map[int64]int64{1: 11},
+	}
+	report, err := client.CloneProduct(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CloneProduct() error = %v", err)
+	}
+	if report.TargetProductId != 9 || len(report.Dropped) != 5 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if !reflect.DeepEqual(switched, []string{"200", "100"}) {
+		t.Errorf("switched = %v, want [200 100]", switched)
+	}
+	product := created.ProductInfo
+	if product.ID != 0 || product.ModelList[0].ID != 0 || product.ModelList[0].InputNormalPrice != 3500 || product.VideoList != nil {
+		t.Errorf("unexpected created product %+v", product)
+	}
+	if !reflect.DeepEqual(product.LogisticsChannels, []LogisticsChannel{{ChannelID: 11, Enabled: true}}) {
+		t.Errorf("unexpected logistics channels %+v", product.LogisticsChannels)
+	}
+	if !product.PreOrderInfo.PreOrder || product.PreOrderInfo.DaysToShip != 7 || product.Images[0] != "img1" {
+		t.Errorf("days to ship and images should be carried over, got %+v", product)
+	}
+	if !reflect.DeepEqual(product.CategoryPath, []int64{200, 201}) || product.Attributes != nil ||
+		product.BrandInfo != (ProductBrandInfo{}) || product.SizeChart != "" {
+		t.Errorf("category bound fields should be cleared, got %+v", product)
+	}
+
+	var shopeeErr *ShopeeError
+	req.CategoryPath = nil
+	if _, err := client.CloneProduct(context.Background(), req); !errors.As(err, &shopeeErr) || shopeeErr.Type != ErrTypeValidation {
+		t.Errorf("cross region clone without category should fail validation, got %v", err)
+	}
+	req.CategoryPath = []int64{200, 201}
+	req.PriceRate = 0
+	if _, err := client.CloneProduct(context.Background(), req); !errors.As(err, &shopeeErr) || shopeeErr.Type != ErrTypeValidation {
+		t.Errorf("cross currency clone without rate should fail validation, got %v", err)
+	}
+	req.TargetShopId = "300"
+	if _, err := client.CloneProduct(context.Background(), req); !errors.As(err, &shopeeErr) || shopeeErr.Type != ErrTypeValidation {
+		t.Errorf("shop outside the merchant should fail validation, got %v", err)
+	}
+}
+
+func TestRemapProductForCloneSameRegion(t *testing.T) {
+	source := ProductDefinition{
+		CategoryPath: []int64{100, 101},
+		Attributes:   []ProductAttribute{{AttributeID: 5, ValueID: 6}},
+		SizeChart:    "chart1",
+	}
+	req := CloneProductRequest{SourceRegion: "SG", TargetRegion: "SG"}
+	product, dropped, err := remapProductForClone(source, req)
+	if err != nil || len(dropped) != 0 || len(product.Attributes) != 1 || product.SizeChart != "chart1" {
+		t.Errorf("same category clone should keep attributes, got %+v, dropped = %+v, err = %v", product, dropped, err)
+	}
+
+	req.CategoryPath = []int64{100, 102}
+	product, dropped, err = remapProductForClone(source, req)
+	if err != nil || len(dropped) != 2 || product.Attributes != nil || product.SizeChart != "" {
+		t.Errorf("changed category should drop attributes and size chart, got %+v, dropped = %+v, err = %v", product, dropped, err)
+	}
+}
@@ -1,10 +1,49 @@
 package shopee
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/donghui12/shopee_tool_base/pkg/constant"
+)
+
+// OpenPlatformEnv Open Platform 环境，决定请求地址与签名使用的 partner
+type OpenPlatformEnv string
+
+const (
+	OpenPlatformEnvLive    OpenPlatformEnv = "live"    // 正式环境
+	OpenPlatformEnvSandbox OpenPlatformEnv = "sandbox" // 测试环境 test-stable
 )
 
+// ParseOpenPlatformEnv 解析环境名称，空字符串视为正式环境
+func ParseOpenPlatformEnv(name string) (OpenPlatformEnv, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "live", "prod", "production":
+		return OpenPlatformEnvLive, nil
+	case "sandbox", "test", "test-stable":
+		return OpenPlatformEnvSandbox, nil
+	}
+	return "", fmt.Errorf("未知的 Open Platform 环境: %s", name)
+}
+
+// BaseURL 返回环境对应的请求地址
+func (env OpenPlatformEnv) BaseURL() string {
+	if env == OpenPlatformEnvSandbox {
+		return BaseTestSellerURLForTw
+	}
+	return BaseLiveSellerURLForTw
+}
+
+// Partner 返回环境对应的 partner_id 与 partner_key
+func (env OpenPlatformEnv) Partner() (string, string) {
+	if env == OpenPlatformEnvSandbox {
+		return constant.TestPartnerId, constant.TestPartnerKey
+	}
+	return constant.LivePartnerId, constant.LivePartnerKey
+}
+
 // ClientConfig 客户端配置
 type ClientConfig struct {
 	BaseURL    string
@@ -21,6 +60,11 @@ type ClientConfig struct {
 	// 代理配置
 	UseProxy      bool
 	ProxyRotation bool
+
+	// Open Platform 配置，PartnerId/PartnerKey 为空时使用环境对应的 partner
+	OpenPlatformEnv OpenPlatformEnv
+	PartnerId       string
+	PartnerKey      string
 }
 
 // DefaultConfig 返回默认配置
@@ -44,6 +88,16 @@ func TaiwanConfig() *ClientConfig {
 	config := DefaultConfig()
 	config.BaseURL = BaseSellerURLForTw
 	config.RetryDelay = 5 * time.Second
+	config.OpenPlatformEnv = OpenPlatformEnvLive
+	return config
+}
+
+// OpenPlatformConfig 返回指定 Open Platform 环境的配置，地址与 partner 一起切换
+// 需要指向本地模拟服务时，可在返回后覆盖 BaseURL
+func OpenPlatformConfig(env OpenPlatformEnv) *ClientConfig {
+	config := TaiwanConfig()
+	config.OpenPlatformEnv = env
+	config.BaseURL = env.BaseURL()
 	return config
 }
 
@@ -74,7 +128,10 @@ func (config *ClientConfig) ApplyConfig(client *Client) {
 	client.retryTimes = config.RetryTimes
 	client.retryDelay = config.RetryDelay
 	client.timeout = config.Timeout
-	
+	client.openPlatformEnv = config.OpenPlatformEnv
+	client.partnerId = config.PartnerId
+	client.partnerKey = config.PartnerKey
+
 	// 配置HTTP传输
 	transport := &http.Transport{
 		MaxIdleConns:        config.MaxIdleConns,
@@ -104,6 +161,10 @@ func NewTaiwanClient() *Client {
 	return NewClientWithConfig(TaiwanConfig())
 }
 
+func NewOpenPlatformClient(env OpenPlatformEnv) *Client {
+	return NewClientWithConfig(OpenPlatformConfig(env))
+}
+
 func NewHighPerformanceClient() *Client {
 	return NewClientWithConfig(HighPerformanceConfig())
 }
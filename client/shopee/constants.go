@@ -17,7 +17,12 @@ const (
 	APIPathGetMerchantShopList = "/api/cnsc/selleraccount/get_merchant_shop_list/"
 	APIPathGetOrSetShop        = "/api/cnsc/selleraccount/get_or_set_shop/"
 	APIPathProductDetailList   = "/api/v3/product/search_product_list_v2/"
+	APIPathSearchProductList   = "/api/v3/mpsku/list/v2/search_product_list"
 	APIPathDeleteProduct       = "/api/v3/product/delete_product/"
+	APIPathGetProductInfo      = "/api/v3/product/get_product_info"
+	APIPathGetPriceStockInfo   = "/api/v3/product/get_price_stock_info/"
+	APIPathUpdatePriceStock    = "/api/v3/product/update_price_stock/"
+	APIPathCreateProduct       = "/api/v3/product/create_product_info/"
 
 	// 折扣相关接口
 	APIPathGetDiscountList    = "/api/marketing/v3/public/discount/list/"
@@ -38,8 +43,22 @@ const (
 	APIPathAuthTokenForTw                 = "/api/v2/auth/token/get"
 	APIPathAccessTokenForTw               = "/api/v2/auth/access_token/get"
 	APIPathGetBaseProductInfo             = "/api/v2/product/get_item_base_info"
+	APIPathUnlistItemForTw                = "/api/v2/product/unlist_item"
+	APIPathDeleteItemForTw                = "/api/v2/product/delete_item"
+	APIPathUpdatePriceForTw               = "/api/v2/product/update_price"
+	APIPathUpdateStockForTw               = "/api/v2/product/update_stock"
+	APIPathOrderListForTw                 = "/api/v2/order/get_order_list"
+	APIPathOrderDetailForTw               = "/api/v2/order/get_order_detail"
+	APIPathShippingParameterForTw         = "/api/v2/logistics/get_shipping_parameter"
 	APIPathBatchUpdateProductInfo         = "/api/v3/product/update_product/"
 	APIPathBatchUpdateProductInfoWithFile = "/api/mass/mpsku/upload_edit_template/"
+	APIPathMassUploadTask                 = "/api/mass/mpsku/get_upload_task/"
+)
+
+// Open Platform 环境变量
+const (
+	EnvKeyOpenPlatformEnv     = "SHOPEE_OPEN_PLATFORM_ENV"      // live / sandbox
+	EnvKeyOpenPlatformBaseURL = "SHOPEE_OPEN_PLATFORM_BASE_URL" // 覆盖请求地址，例如本地模拟服务
 )
 
 // API 请求方法
@@ -82,6 +101,24 @@ const (
 	ListTypeDelisted = "delisted"
 )
 
+// 商品搜索类型
+const (
+	SearchTypeName   = "name"
+	SearchTypeSku    = "sku"
+	SearchTypeItemId = "item_id"
+)
+
+// 商品搜索排序
+const (
+	SortByCreateTime = "create_time"
+	SortBySold       = "sold"
+	SortByStock      = "stock"
+	SortByPrice      = "price"
+
+	SortTypeAsc  = 1
+	SortTypeDesc = 2
+)
+
 // 上下架状态
 const (
 	ListedStatus   = true
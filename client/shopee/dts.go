@@ -0,0 +1,258 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// DaysToShipFilter 批量修改出货天数的商品范围
+type DaysToShipFilter struct {
+	ProductIds        []int64 // 只处理这些商品，为空表示全部在售商品
+	ExcludeIds        []int64 // 不处理的商品
+	CurrentDaysToShip []int   // 只处理当前出货天数为这些值的商品，为空表示不限制
+}
+
+// BulkDaysToShipOptions 批量修改出货天数的配置
+type BulkDaysToShipOptions struct {
+	BatchSize  int           // 每次批量更新的商品数，默认 50
+	Interval   time.Duration // 两次批量请求之间的间隔，默认 2s
+	MaxRetries int           // 可重试失败与未生效商品的最大重试轮数，默认 2，小于 0 表示不重试
+	RetryDelay time.Duration // 每轮重试前的等待时间，默认 5s
+}
+
+// DaysToShipFailure 修改失败的商品
+type DaysToShipFailure struct {
+	ProductId int64  `json:"product_id"`
+	Reason    string `json:"reason"`
+}
+
+// DaysToShipReport 批量修改出货天数的对账结果
+type DaysToShipReport struct {
+	Target    int                 `json:"target"`
+	Scanned   int                 `json:"scanned"`   // 范围内的商品数
+	Unchanged []int64             `json:"unchanged"` // 修改前已是目标值
+	Updated   []int64             `json:"updated"`   // 已修改并校验通过
+	Failed    []DaysToShipFailure `json:"failed"`
+	Skipped   []DaysToShipFailure `json:"skipped"` // 指定了但不在售或不存在的商品
+}
+
+func (opts *BulkDaysToShipOptions) setDefaults() {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = 2
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = 5 * time.Second
+	}
+}
+
+// match 判断商品是否在处理范围内
+func (f DaysToShipFilter) match(product ProductDetail) bool {
+	productId := int64(product.ID)
+	for _, id := range f.ExcludeIds {
+		if id == productId {
+			return false
+		}
+	}
+	if len(f.ProductIds) > 0 {
+		found := false
+		for _, id := range f.ProductIds {
+			if id == productId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.CurrentDaysToShip) > 0 {
+		for _, days := range f.CurrentDaysToShip {
+			if days == product.DaysToShip {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// missing 返回指定了但不在 live 中的商品，排除的商品不计入
+func (f DaysToShipFilter) missing(live map[int64]bool) []DaysToShipFailure {
+	var skipped []DaysToShipFailure
+	seen := make(map[int64]bool, len(f.ProductIds))
+	for _, id := range f.ExcludeIds {
+		seen[id] = true
+	}
+	for _, id := range f.ProductIds {
+		if live[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		skipped = append(skipped, DaysToShipFailure{ProductId: id, Reason: "商品不在售或不存在"})
+	}
+	return skipped
+}
+
+// BulkUpdateDaysToShip 将在售商品的出货天数改为 target
+// 只处理在售商品：批量接口修改出货天数时会同时上架商品
+// 流程：读取当前值 -> 分批限速更新并解析每个商品的结果 -> 重新读取校验 -> 对未生效或可重试的商品重试
+func (c *Client) BulkUpdateDaysToShip(ctx context.Context, cookies, shopId, region string, target int,
+	filter DaysToShipFilter, opts BulkDaysToShipOptions) (*DaysToShipReport, error) {
+	if target <= 0 {
+		return nil, NewValidationError("出货天数必须大于 0")
+	}
+	opts.setDefaults()
+	report := &DaysToShipReport{Target: target}
+
+	products, err := c.GetProductDetailListWithDayToShip(cookies, shopId, region, ListTypeLive)
+	if err != nil {
+		return nil, fmt.Errorf("获取商品出货天数失败: %w", err)
+	}
+	var pending []int64
+	live := make(map[int64]bool, len(products))
+	for _, product := range products {
+		live[int64(product.ID)] = true
+		if !filter.match(product) {
+			continue
+		}
+		report.Scanned++
+		if product.DaysToShip == target {
+			report.Unchanged = append(report.Unchanged, int64(product.ID))
+			continue
+		}
+		pending = append(pending, int64(product.ID))
+	}
+	report.Skipped = filter.missing(live)
+
+	req := UpdateProductInfoReq{DaysToShip: target, Cookies: cookies, ShopID: shopId, Region: region}
+	lastReason := make(map[int64]string)
+	for attempt := 0; attempt <= opts.MaxRetries && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			case <-time.After(opts.RetryDelay):
+			}
+		}
+
+		sent, retry, err := c.updateDaysToShipInBatches(ctx, req, pending, opts, lastReason, report)
+		if err != nil {
+			return report, err
+		}
+
+		// 重新读取校验，批量接口返回成功不代表已生效
+		current, err := c.GetProductDetailListWithDayToShip(cookies, shopId, region, ListTypeLive)
+		if err != nil {
+			logger.Error("校验出货天数失败", zap.String("shop_id", shopId), zap.Error(err))
+			for _, productId := range sent {
+				lastReason[productId] = "校验失败: " + err.Error()
+			}
+			pending = append(retry, sent...)
+			continue
+		}
+		currentDays := make(map[int64]int, len(current))
+		for _, product := range current {
+			currentDays[int64(product.ID)] = product.DaysToShip
+		}
+		for _, productId := range sent {
+			if days, ok := currentDays[productId]; ok && days == target {
+				report.Updated = append(report.Updated, productId)
+				continue
+			}
+			lastReason[productId] = "更新未生效"
+			retry = append(retry, productId)
+		}
+		pending = retry
+
+		logger.Info("出货天数批量更新", zap.String("shop_id", shopId), zap.Int("attempt", attempt+1),
+			zap.Int("updated", len(report.Updated)), zap.Int("pending", len(pending)))
+	}
+
+	for _, productId := range pending {
+		report.Failed = append(report.Failed, DaysToShipFailure{ProductId: productId, Reason: lastReason[productId]})
+	}
+	return report, nil
+}
+
+// updateDaysToShipInBatches 分批限速提交，返回接口确认成功的商品与需要重试的商品，不可重试的失败直接写入 report
+func (c *Client) updateDaysToShipInBatches(ctx context.Context, req UpdateProductInfoReq, productIds []int64,
+	opts BulkDaysToShipOptions, lastReason map[int64]string, report *DaysToShipReport) ([]int64, []int64, error) {
+	var sent, retry []int64
+	for start := 0; start < len(productIds); start += opts.BatchSize {
+		if start > 0 {
+			select {
+			case <-ctx.Done():
+				return sent, retry, ctx.Err()
+			case <-time.After(opts.Interval):
+			}
+		}
+		end := start + opts.BatchSize
+		if end > len(productIds) {
+			end = len(productIds)
+		}
+		batch := productIds[start:end]
+
+		items, err := c.BatchUpdateProductInfoWithV3(req, batch, SourceSellerCenter, "")
+		if err != nil && len(items) == 0 {
+			// 请求级别的失败(网络、限流等)整批重试
+			for _, productId := range batch {
+				lastReason[productId] = err.Error()
+			}
+			retry = append(retry, batch...)
+			continue
+		}
+
+		results := make(map[int64]BatchUpdateProductInfoRespItem, len(items))
+		for _, item := range items {
+			results[item.ID] = item
+		}
+		for _, productId := range batch {
+			item, ok := results[productId]
+			switch {
+			case !ok && err != nil:
+				lastReason[productId] = err.Error()
+				retry = append(retry, productId)
+			case !ok || item.Code == ResponseCodeSuccess:
+				sent = append(sent, productId)
+			case isRetryableBatchItem(item):
+				lastReason[productId] = batchItemReason(item)
+				retry = append(retry, productId)
+			default:
+				report.Failed = append(report.Failed, DaysToShipFailure{ProductId: productId, Reason: batchItemReason(item)})
+			}
+		}
+	}
+	return sent, retry, nil
+}
+
+// isRetryableBatchItem 根据返回信息判断单个商品的失败是否为临时错误
+func isRetryableBatchItem(item BatchUpdateProductInfoRespItem) bool {
+	message := strings.ToLower(item.Message + " " + item.UserMessage)
+	for _, keyword := range []string{"rate limit", "too many requests", "busy", "timeout", "try again", "system error", "server error"} {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func batchItemReason(item BatchUpdateProductInfoRespItem) string {
+	if item.UserMessage != "" {
+		return fmt.Sprintf("code=%d, %s", item.Code, item.UserMessage)
+	}
+	return fmt.Sprintf("code=%d, %s", item.Code, item.Message)
+}
@@ -0,0 +1,89 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBulkUpdateDaysToShip(t *testing.T) {
+	var mu sync.Mutex
+	daysToShip := map[int64]int{1: 3, 2: 7, 3: 3, 4: 3}
+	attempts := map[int64]int{}
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case APIPathProductDetailList:
+			var list []string
+			for id, days := range daysToShip {
+				list = append(list, fmt.Sprintf(`{"id":%d,"days_to_ship":%d}`, id, days))
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":4},"list":[`+strings.Join(list, ",")+`]}}`)
+		case APIPathBatchUpdateProductInfo:
+			var req []BatchUpdateProductInfoItem
+			json.NewDecoder(r.Body).Decode(&req)
+			var result []string
+			for _, item := range req {
+				attempts[item.ID]++
+				switch {
+				case item.ID == 3 && attempts[item.ID] == 1:
+					result = append(result, `{"id":3,"code":1,"message":"system busy"}`)
+				case item.ID == 4:
+					result = append(result, `{"id":4,"code":2,"user_message":"days_to_ship exceeds the limit"}`)
+				default:
+					daysToShip[item.ID] = item.DaysToShip
+					result = append(result, fmt.Sprintf(`{"id":%d,"code":0}`, item.ID))
+				}
+			}
+			io.WriteString(w, `{"code":0,"data":{"result":[`+strings.Join(result, ",")+`]}}`)
+		}
+	})
+	defer server.Close()
+
+	report, err := client.BulkUpdateDaysToShip(context.Background(), "SPC_EC=1;", "1", "sg", 7,
+		DaysToShipFilter{}, BulkDaysToShipOptions{BatchSize: 2, Interval: time.Millisecond, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("BulkUpdateDaysToShip() error = %v", err)
+	}
+	if report.Scanned != 4 || len(report.Unchanged) != 1 || report.Unchanged[0] != 2 {
+		t.Errorf("Unexpected scan result: %+v", report)
+	}
+	if len(report.Updated) != 2 {
+		t.Errorf("Expected items 1 and 3 updated, got %v", report.Updated)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].ProductId != 4 {
+		t.Errorf("Expected item 4 failed without retry, got %+v", report.Failed)
+	}
+	if attempts[3] != 2 || attempts[4] != 1 {
+		t.Errorf("Unexpected attempts: %v", attempts)
+	}
+}
+
+func TestBulkUpdateDaysToShipSkipped(t *testing.T) {
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPathProductDetailList:
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":1,"days_to_ship":7}]}}`)
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	// 2 已下架或不存在，3 被排除
+	report, err := client.BulkUpdateDaysToShip(context.Background(), "SPC_EC=1;", "1", "sg", 7,
+		DaysToShipFilter{ProductIds: []int64{1, 2, 2, 3}, ExcludeIds: []int64{3}}, BulkDaysToShipOptions{})
+	if err != nil {
+		t.Fatalf("BulkUpdateDaysToShip() error = %v", err)
+	}
+	if len(report.Unchanged) != 1 || len(report.Skipped) != 1 || report.Skipped[0].ProductId != 2 {
+		t.Errorf("Expected item 2 reported as skipped, got %+v", report)
+	}
+}
@@ -1,8 +1,10 @@
 package shopee
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // ErrType 定义错误类型
@@ -25,9 +27,16 @@ type ShopeeError struct {
 	Message    string
 	Err        error
 	StatusCode int // HTTP状态码
+
+	// Open Platform v2 接口返回的错误码与请求 id
+	ErrorCode string
+	RequestID string
 }
 
 func (e *ShopeeError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("[%s:%s] %s (request_id=%s)", e.Type, e.ErrorCode, e.Message, e.RequestID)
+	}
 	if e.Err != nil {
 		return fmt.Sprintf("[%s:%d] %s: %v", e.Type, e.Code, e.Message, e.Err)
 	}
@@ -54,9 +63,27 @@ func (e *ShopeeError) IsRetryable() bool {
 	if e.Type == ErrTypeNetwork {
 		return true
 	}
+	if e.Type == ErrTypeAPI && isServerOpenAPIError(e.ErrorCode) {
+		return true
+	}
 	return false
 }
 
+// IsRetryableError 判断任意错误(包括被包装的 ShopeeError)是否可重试
+func IsRetryableError(err error) bool {
+	var shopeeErr *ShopeeError
+	if errors.As(err, &shopeeErr) {
+		return shopeeErr.IsRetryable()
+	}
+	return false
+}
+
+// IsRateLimitError 判断任意错误(包括被包装的 ShopeeError)是否为限流错误
+func IsRateLimitError(err error) bool {
+	var shopeeErr *ShopeeError
+	return errors.As(err, &shopeeErr) && shopeeErr.IsType(ErrTypeRateLimit)
+}
+
 // 预定义的错误创建函数
 func NewAuthError(code int, message string, err error) *ShopeeError {
 	return &ShopeeError{
@@ -106,6 +133,41 @@ func NewParsingError(message string, err error) *ShopeeError {
 	}
 }
 
+// NewOpenAPIError 根据 Open Platform v2 响应中的 error 字段创建错误
+func NewOpenAPIError(errorCode, message, requestID string, statusCode int) *ShopeeError {
+	return &ShopeeError{
+		Type:       classifyOpenAPIError(errorCode),
+		Message:    message,
+		StatusCode: statusCode,
+		ErrorCode:  errorCode,
+		RequestID:  requestID,
+	}
+}
+
+// classifyOpenAPIError 将 v2 的字符串错误码归类
+func classifyOpenAPIError(errorCode string) ErrType {
+	code := strings.ToLower(errorCode)
+	switch {
+	case strings.Contains(code, "auth"), strings.Contains(code, "token"),
+		strings.Contains(code, "sign"), strings.Contains(code, "permission"):
+		return ErrTypeAuth
+	case strings.Contains(code, "too_many"), strings.Contains(code, "rate_limit"),
+		strings.Contains(code, "frequent"):
+		return ErrTypeRateLimit
+	case strings.Contains(code, "param"), strings.Contains(code, "not_found"),
+		strings.Contains(code, "invalid"):
+		return ErrTypeValidation
+	}
+	return ErrTypeAPI
+}
+
+// isServerOpenAPIError 判断 v2 错误码是否为服务端临时错误
+func isServerOpenAPIError(errorCode string) bool {
+	code := strings.ToLower(errorCode)
+	return strings.Contains(code, "server") || strings.Contains(code, "inner") ||
+		strings.Contains(code, "busy") || strings.Contains(code, "timeout")
+}
+
 // 常见错误处理函数
 func HandleHTTPError(resp *http.Response, body []byte) *ShopeeError {
 	switch resp.StatusCode {
@@ -0,0 +1,96 @@
+package shopee
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 活跃度评分中各项指标的权重，销量最能说明商品仍有价值
+const (
+	inactivityViewWeight = 1
+	inactivityLikeWeight = 5
+	inactivitySoldWeight = 20
+)
+
+// InactivityPolicy 不活跃商品的判定策略，各项条件需同时满足
+type InactivityPolicy struct {
+	MaxViews                int64   // 浏览数不超过该值
+	MaxLikes                int64   // 点赞数不超过该值
+	MaxSold                 int64   // 销量不超过该值
+	MinAgeDays              int     // 创建至今的最少天数，0 表示不限制
+	ExcludeOngoingCampaigns bool    // 排除正在参与活动的商品
+	ProtectedIds            []int64 // 永不入选的商品
+}
+
+// InactiveCandidate 入选的不活跃商品
+type InactiveCandidate struct {
+	ProductId  int64    `json:"product_id"`
+	Name       string   `json:"name"`
+	CreateTime int64    `json:"create_time"`
+	Score      float64  `json:"score"`   // 越高越不活跃
+	Reasons    []string `json:"reasons"` // 入选原因
+}
+
+// DefaultInactivityPolicy 与原有规则一致：点赞、销量、浏览全为 0
+func DefaultInactivityPolicy() InactivityPolicy {
+	return InactivityPolicy{}
+}
+
+// Evaluate 筛选不活跃商品并按评分从高到低排序，评分相同时创建时间早的在前
+// 评分 = 上架天数 / (1 + 加权活跃度)，活跃度全为 0 时等价于按创建时间排序
+func (p InactivityPolicy) Evaluate(products []Product, now time.Time) []InactiveCandidate {
+	protected := make(map[int64]bool, len(p.ProtectedIds))
+	for _, id := range p.ProtectedIds {
+		protected[id] = true
+	}
+
+	var candidates []InactiveCandidate
+	for _, product := range products {
+		productId := int64(product.ID)
+		if protected[productId] {
+			continue
+		}
+		if p.ExcludeOngoingCampaigns && len(product.PromotionDetail.OngoingCampaigns) > 0 {
+			continue
+		}
+
+		stat := product.Statistics
+		if stat.ViewCount > p.MaxViews || stat.LikedCount > p.MaxLikes || stat.SoldCount > p.MaxSold {
+			continue
+		}
+		ageDays := now.Sub(time.Unix(product.CreateTime, 0)).Hours() / 24
+		if p.MinAgeDays > 0 && ageDays < float64(p.MinAgeDays) {
+			continue
+		}
+
+		activity := stat.ViewCount*inactivityViewWeight + stat.LikedCount*inactivityLikeWeight + stat.SoldCount*inactivitySoldWeight
+		reasons := []string{
+			fmt.Sprintf("浏览 %d ≤ %d", stat.ViewCount, p.MaxViews),
+			fmt.Sprintf("点赞 %d ≤ %d", stat.LikedCount, p.MaxLikes),
+			fmt.Sprintf("销量 %d ≤ %d", stat.SoldCount, p.MaxSold),
+		}
+		if p.MinAgeDays > 0 {
+			reasons = append(reasons, fmt.Sprintf("已创建 %d 天 ≥ %d 天", int(ageDays), p.MinAgeDays))
+		}
+		if p.ExcludeOngoingCampaigns {
+			reasons = append(reasons, "未参与进行中的活动")
+		}
+
+		candidates = append(candidates, InactiveCandidate{
+			ProductId:  productId,
+			Name:       product.Name,
+			CreateTime: product.CreateTime,
+			Score:      ageDays / float64(1+activity),
+			Reasons:    reasons,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].CreateTime < candidates[j].CreateTime
+	})
+	return candidates
+}
@@ -0,0 +1,60 @@
+package shopee
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestInactivityPolicyEvaluate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	daysAgo := func(days int) int64 {
+		return now.Add(-time.Duration(days) * 24 * time.Hour).Unix()
+	}
+	products := []Product{
+		{ID: 1, CreateTime: daysAgo(100)},
+		{ID: 2, CreateTime: daysAgo(200), Statistics: ProductStatistics{ViewCount: 3}},
+		{ID: 3, CreateTime: daysAgo(300), Statistics: ProductStatistics{SoldCount: 1}},
+		{ID: 4, CreateTime: daysAgo(10)},
+		{ID: 5, CreateTime: daysAgo(400), PromotionDetail: PromotionDetail{OngoingCampaigns: []OngoingCampaigns{{ProductID: 5}}}},
+		{ID: 6, CreateTime: daysAgo(500)},
+	}
+
+	// 默认策略与原规则一致：只选活跃度全为 0 的商品，越旧越靠前
+	candidates := DefaultInactivityPolicy().Evaluate(products, now)
+	var ids []int64
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ProductId)
+	}
+	if len(ids) != 4 || ids[0] != 6 || ids[1] != 5 || ids[2] != 1 || ids[3] != 4 {
+		t.Errorf("Unexpected default candidates %v", ids)
+	}
+
+	policy := InactivityPolicy{
+		MaxViews:                5,
+		MinAgeDays:              30,
+		ExcludeOngoingCampaigns: true,
+		ProtectedIds:            []int64{6},
+	}
+	candidates = policy.Evaluate(products, now)
+	if len(candidates) != 2 || candidates[0].ProductId != 1 || candidates[1].ProductId != 2 {
+		t.Fatalf("Unexpected candidates %+v", candidates)
+	}
+	if len(candidates[0].Reasons) != 5 {
+		t.Errorf("Expected reasons for each criterion, got %v", candidates[0].Reasons)
+	}
+}
+
+func TestGetInactiveProductsNonPositiveBatch(t *testing.T) {
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s", r.URL.Path)
+	})
+	defer server.Close()
+
+	for _, batch := range []int{0, -1} {
+		productIds, err := client.GetInactiveProducts("SPC_EC=1;", "1", "sg", batch)
+		if err != nil || len(productIds) != 0 {
+			t.Errorf("GetInactiveProducts(batch=%d) = %v, %v, want none", batch, productIds, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package shopee
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/constant"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// ListingAction 上下架动作
+type ListingAction int
+
+const (
+	// ListingActionList 上架
+	ListingActionList ListingAction = iota + 1
+	// ListingActionUnlist 下架
+	ListingActionUnlist
+)
+
+func (a ListingAction) String() string {
+	switch a {
+	case ListingActionList:
+		return constant.ActionListed
+	case ListingActionUnlist:
+		return constant.ActionUnlisted
+	}
+	return "unknown"
+}
+
+// ListingOptions 批量上下架的配置，遇到限流时缩小批量并加大间隔，之后逐步恢复
+type ListingOptions struct {
+	BatchSize    int           // 每次批量请求的商品数，默认 50
+	MinBatchSize int           // 限流时批量缩小的下限，默认 5
+	Interval     time.Duration // 两次批量请求之间的间隔，默认 2s
+	MaxInterval  time.Duration // 限流时间隔增大的上限，默认 30s
+	MaxRetries   int           // 可重试的商品重新排队的次数，默认 2，小于 0 表示不重试
+}
+
+func (opts *ListingOptions) setDefaults() {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.MinBatchSize <= 0 {
+		opts.MinBatchSize = 5
+	}
+	if opts.MinBatchSize > opts.BatchSize {
+		opts.MinBatchSize = opts.BatchSize
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = 30 * time.Second
+		if opts.MaxInterval < opts.Interval {
+			opts.MaxInterval = opts.Interval
+		}
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = 2
+	}
+}
+
+// ListingOutcome 单个商品的上下架结果，Code 为接口返回的错误码，请求被限流时为 429
+type ListingOutcome struct {
+	ProductId int64  `json:"product_id"`
+	Success   bool   `json:"success"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Attempts  int    `json:"attempts"`
+}
+
+// ListedOrUnlistedProducts 使用 V3 批量接口上下架商品，返回每个商品的结果，顺序与 productIds 一致
+// 限流、请求失败与可重试的商品错误会重新排队，超过重试次数后记为失败
+func (c *Client) ListedOrUnlistedProducts(ctx context.Context, shopId, cookies, region string, action ListingAction,
+	productIds []int64, opts ListingOptions) ([]ListingOutcome, error) {
+	if action != ListingActionList && action != ListingActionUnlist {
+		return nil, NewValidationError("未知的上下架动作")
+	}
+	opts.setDefaults()
+	req := UpdateProductInfoReq{Cookies: cookies, ShopID: shopId, Region: region}
+
+	outcomes := make(map[int64]*ListingOutcome, len(productIds))
+	var queue []int64
+	for _, productId := range productIds {
+		if _, ok := outcomes[productId]; ok {
+			continue
+		}
+		outcomes[productId] = &ListingOutcome{ProductId: productId}
+		queue = append(queue, productId)
+	}
+	// requeue 可重试的失败，超过重试次数时保留最后一次的错误
+	requeue := func(productId int64, code int, message string) {
+		outcome := outcomes[productId]
+		outcome.Code, outcome.Message = code, message
+		if outcome.Attempts <= opts.MaxRetries {
+			queue = append(queue, productId)
+		}
+	}
+
+	batchSize, interval := opts.BatchSize, opts.Interval
+	for sent := 0; len(queue) > 0; sent++ {
+		if sent > 0 {
+			select {
+			case <-ctx.Done():
+				return listingOutcomes(productIds, outcomes), ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		batch := queue[:minInt(batchSize, len(queue))]
+		queue = queue[len(batch):]
+		for _, productId := range batch {
+			outcomes[productId].Attempts++
+		}
+
+		items, err := c.BatchUpdateProductInfoWithV3(req, batch, SourceSellerCenter, action.String())
+		if err != nil && len(items) == 0 {
+			code := 0
+			if IsRateLimitError(err) {
+				code = RateLimitCode
+				batchSize = maxInt(batchSize/2, opts.MinBatchSize)
+				interval = minDuration(interval*2, opts.MaxInterval)
+				logger.Warn("上下架被限流，缩小批量", zap.String("shop_id", shopId),
+					zap.Int("batch_size", batchSize), zap.Duration("interval", interval))
+			}
+			for _, productId := range batch {
+				requeue(productId, code, err.Error())
+			}
+			continue
+		}
+		// 请求成功后逐步恢复批量与间隔
+		batchSize = minInt(batchSize*2, opts.BatchSize)
+		interval = maxDuration(interval/2, opts.Interval)
+
+		results := make(map[int64]BatchUpdateProductInfoRespItem, len(items))
+		for _, item := range items {
+			results[item.ID] = item
+		}
+		for _, productId := range batch {
+			item, ok := results[productId]
+			outcome := outcomes[productId]
+			switch {
+			case !ok && err != nil:
+				requeue(productId, 0, err.Error())
+			case !ok || item.Code == ResponseCodeSuccess:
+				outcome.Success, outcome.Code, outcome.Message = true, ResponseCodeSuccess, ""
+			case isRetryableBatchItem(item):
+				requeue(productId, item.Code, batchItemReason(item))
+			default:
+				outcome.Code, outcome.Message = item.Code, batchItemReason(item)
+			}
+		}
+	}
+
+	result := listingOutcomes(productIds, outcomes)
+	succeeded := 0
+	for _, outcome := range result {
+		if outcome.Success {
+			succeeded++
+		}
+	}
+	logger.Info("上下架商品完成", zap.String("shop_id", shopId), zap.String("action", action.String()),
+		zap.Int("total", len(result)), zap.Int("succeeded", succeeded))
+	return result, nil
+}
+
+// listingOutcomes 按 productIds 的顺序整理结果，重复的商品只保留一次
+func listingOutcomes(productIds []int64, outcomes map[int64]*ListingOutcome) []ListingOutcome {
+	result := make([]ListingOutcome, 0, len(outcomes))
+	seen := make(map[int64]bool, len(outcomes))
+	for _, productId := range productIds {
+		if seen[productId] {
+			continue
+		}
+		seen[productId] = true
+		result = append(result, *outcomes[productId])
+	}
+	return result
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
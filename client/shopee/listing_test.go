@@ -0,0 +1,66 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListedOrUnlistedProducts(t *testing.T) {
+	var batches [][]int64
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		var req []BatchUpdateProductInfoItem
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode batch request: %v", err)
+		}
+		var ids []int64
+		for _, item := range req {
+			if !item.Unlisted {
+				t.Errorf("product %d should be unlisted", item.ID)
+			}
+			ids = append(ids, item.ID)
+		}
+		batches = append(batches, ids)
+		if len(batches) == 1 {
+			w.WriteHeader(RateLimitCode)
+			return
+		}
+		var resp BatchUpdateProductInfoResponse
+		for _, id := range ids {
+			switch id {
+			case 2:
+				resp.Data.Result = append(resp.Data.Result, BatchUpdateProductInfoRespItem{ID: 2, Code: 100, Message: "system busy"})
+			case 3:
+				resp.Data.Result = append(resp.Data.Result, BatchUpdateProductInfoRespItem{ID: 3, Code: 1000601, UserMessage: "商品在活动中"})
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	defer server.Close()
+
+	outcomes, err := client.ListedOrUnlistedProducts(context.Background(), "123", "cookie", "SG", ListingActionUnlist,
+		[]int64{1, 2, 3, 1}, ListingOptions{BatchSize: 2, MinBatchSize: 1, Interval: time.Millisecond, MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("ListedOrUnlistedProducts() error = %v", err)
+	}
+	// 第一批被限流后批量缩小为 1，成功后恢复为 2；商品 2 可重试的错误重新排队一次后仍失败
+	wantBatches := [][]int64{{1, 2}, {3}, {1, 2}}
+	if !reflect.DeepEqual(batches, wantBatches) {
+		t.Errorf("batches = %v, want %v", batches, wantBatches)
+	}
+	want := []ListingOutcome{
+		{ProductId: 1, Success: true, Attempts: 2},
+		{ProductId: 2, Code: 100, Message: "code=100, system busy", Attempts: 2},
+		{ProductId: 3, Code: 1000601, Message: "code=1000601, 商品在活动中", Attempts: 1},
+	}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("outcomes = %+v, want %+v", outcomes, want)
+	}
+
+	if _, err := client.ListedOrUnlistedProducts(context.Background(), "123", "cookie", "SG", 0, []int64{1}, ListingOptions{}); err == nil {
+		t.Error("unknown action should fail")
+	}
+}
@@ -0,0 +1,387 @@
+package shopee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/pkg/utils"
+	"github.com/donghui12/shopee_tool_base/pkg/xlsx"
+)
+
+// 批量编辑模板的列
+const (
+	MassEditColumnProductId  = "product_id"
+	MassEditColumnPreOrder   = "pre_order"
+	MassEditColumnDaysToShip = "days_to_ship"
+	MassEditColumnUnlisted   = "unlisted"
+	MassEditColumnFailReason = "fail_reason" // 结果文件中的失败原因
+)
+
+// MassEditSheetName 模板工作表名称
+const MassEditSheetName = "Template"
+
+// MassEditHeader 模板表头，第一行为字段名，第二行为说明，数据从第三行开始
+var MassEditHeader = [][]string{
+	{MassEditColumnProductId, MassEditColumnPreOrder, MassEditColumnDaysToShip, MassEditColumnUnlisted},
+	{"商品ID", "是否预售(Y/N)", "出货天数", "是否下架(Y/N)"},
+}
+
+// 批量编辑任务状态
+const (
+	MassEditTaskProcessing = 1
+	MassEditTaskFinished   = 2
+	MassEditTaskFailed     = 3
+)
+
+// MassEditChange 单个商品的修改，零值字段不修改
+type MassEditChange struct {
+	ProductId  int64
+	DaysToShip int
+	PreOrder   *bool // 为空且修改出货天数时，2 天视为非预售
+	Unlisted   *bool
+}
+
+// MassEditTask 批量编辑任务
+type MassEditTask struct {
+	TaskId        string `json:"task_id"`
+	Status        int    `json:"status"`
+	TotalCount    int    `json:"total_count"`
+	SuccessCount  int    `json:"success_count"`
+	FailCount     int    `json:"fail_count"`
+	ResultFileURL string `json:"result_file_url"`
+}
+
+// MassEditRowResult 结果文件中的一行
+type MassEditRowResult struct {
+	Row       int    `json:"row"` // 从 1 开始的行号
+	ProductId int64  `json:"product_id"`
+	Success   bool   `json:"success"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// MassEditReport 批量编辑的最终结果
+type MassEditReport struct {
+	TaskId    string              `json:"task_id"`
+	Task      *MassEditTask       `json:"task"`
+	Succeeded []int64             `json:"succeeded"`
+	Failed    []MassEditRowResult `json:"failed"`
+}
+
+// MassEditOptions 批量编辑的配置
+type MassEditOptions struct {
+	Dir          string        // 模板文件目录，默认系统临时目录
+	KeepFile     bool          // 完成后保留模板文件
+	PollInterval time.Duration // 轮询任务状态的间隔，默认 5s
+	Timeout      time.Duration // 等待任务完成的最长时间，默认 10 分钟
+}
+
+type massUploadResponse struct {
+	Code        int    `json:"code"`
+	Message     string `json:"message"`
+	UserMessage string `json:"user_message"`
+	Data        struct {
+		TaskId string `json:"task_id"`
+	} `json:"data"`
+}
+
+type massEditTaskResponse struct {
+	Code        int          `json:"code"`
+	Message     string       `json:"message"`
+	UserMessage string       `json:"user_message"`
+	Data        MassEditTask `json:"data"`
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "Y"
+	}
+	return "N"
+}
+
+// BuildMassEditTemplate 根据商品修改生成批量编辑模板
+func BuildMassEditTemplate(w io.Writer, changes []MassEditChange) error {
+	rows := make([][]string, 0, len(MassEditHeader)+len(changes))
+	rows = append(rows, MassEditHeader...)
+	for _, change := range changes {
+		if change.ProductId == 0 {
+			return NewValidationError("product_id 不能为空")
+		}
+		row := []string{strconv.FormatInt(change.ProductId, 10), "", "", ""}
+		if change.DaysToShip > 0 {
+			row[2] = strconv.Itoa(change.DaysToShip)
+			preOrder := change.DaysToShip != 2
+			if change.PreOrder != nil {
+				preOrder = *change.PreOrder
+			}
+			row[1] = yesNo(preOrder)
+		} else if change.PreOrder != nil {
+			row[1] = yesNo(*change.PreOrder)
+		}
+		if change.Unlisted != nil {
+			row[3] = yesNo(*change.Unlisted)
+		}
+		rows = append(rows, row)
+	}
+	return xlsx.Write(w, MassEditSheetName, rows)
+}
+
+// ParseMassEditResult 解析任务结果文件，失败原因为空的行视为成功
+func ParseMassEditResult(data []byte) ([]MassEditRowResult, error) {
+	rows, err := xlsx.ReadBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	headerRow, idCol, reasonCol := -1, -1, -1
+	for i, row := range rows {
+		for j, cell := range row {
+			switch strings.TrimSpace(cell) {
+			case MassEditColumnProductId:
+				idCol = j
+			case MassEditColumnFailReason:
+				reasonCol = j
+			}
+		}
+		if idCol >= 0 {
+			headerRow = i
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, NewParsingError("结果文件缺少 product_id 列", nil)
+	}
+
+	var results []MassEditRowResult
+	for i := headerRow + 1; i < len(rows); i++ {
+		row := rows[i]
+		if idCol >= len(row) {
+			continue
+		}
+		productId, err := strconv.ParseInt(strings.TrimSpace(row[idCol]), 10, 64)
+		if err != nil {
+			// 说明行等非数据行
+			continue
+		}
+		result := MassEditRowResult{Row: i + 1, ProductId: productId, Success: true}
+		if reasonCol >= 0 && reasonCol < len(row) && strings.TrimSpace(row[reasonCol]) != "" {
+			result.Success = false
+			result.Reason = strings.TrimSpace(row[reasonCol])
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// UploadMassEditFile 上传批量编辑模板，返回批量任务 id
+func (c *Client) UploadMassEditFile(updateProductInfoReq UpdateProductInfoReq, filename string) (string, error) {
+	SPC_CDS := uuid.New().String()
+	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
+
+	updateProductInfoParams := url.Values{}
+	updateProductInfoParams.Set("SPC_CDS", SPC_CDS)
+	updateProductInfoParams.Set("SPC_CDS_VER", "2")
+	updateProductInfoParams.Set("cnsc_shop_id", updateProductInfoReq.ShopID)
+	updateProductInfoParams.Set("cbsc_shop_region", updateProductInfoReq.Region)
+	updateProductInfoParams.Set("timestamp", timestampStr)
+
+	APIUpdateProductInfo := APIPathBatchUpdateProductInfoWithFile + "?" + updateProductInfoParams.Encode()
+	resp, err := c.doRequestWithFile(HTTPMethodPost, APIUpdateProductInfo, updateProductInfoReq.Cookies, "file", filename)
+	if err != nil {
+		return "", fmt.Errorf("update product info failed, request error: %w", err)
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read response body failed: %w", err)
+	}
+	logger.Info("Body", zap.String("body:", string(body)))
+	if resp.StatusCode == RateLimitCode {
+		return "", fmt.Errorf(RateLimitError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("update product info failed, status code: %d, message: %s", resp.StatusCode, string(body))
+	}
+	var uploadResp massUploadResponse
+	err = json.Unmarshal(body, &uploadResp)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal update product info response failed: %w", err)
+	}
+	if uploadResp.Code != ResponseCodeSuccess &&
+		uploadResp.Code != ProcessCode {
+		return "", fmt.Errorf("update product info failed, message: %+v", uploadResp)
+	}
+
+	return uploadResp.Data.TaskId, nil
+}
+
+// GetMassEditTask 查询批量编辑任务状态
+func (c *Client) GetMassEditTask(cookies, shopId, region, taskId string) (*MassEditTask, error) {
+	params := url.Values{}
+	params.Set("SPC_CDS", uuid.New().String())
+	params.Set("SPC_CDS_VER", "2")
+	params.Set("cnsc_shop_id", shopId)
+	params.Set("cbsc_shop_region", region)
+	params.Set("task_id", taskId)
+
+	resp, err := c.doRequest(HTTPMethodGet, APIPathMassUploadTask+"?"+params.Encode(), nil, cookies)
+	if err != nil {
+		return nil, fmt.Errorf("get mass upload task failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get mass upload task failed, status code: %d, message: %s", resp.StatusCode, string(body))
+	}
+	var taskResp massEditTaskResponse
+	if err := json.Unmarshal(body, &taskResp); err != nil {
+		return nil, fmt.Errorf("unmarshal mass upload task response failed: %w", err)
+	}
+	if taskResp.Code != ResponseCodeSuccess {
+		return nil, fmt.Errorf("get mass upload task failed, message: %s", taskResp.UserMessage)
+	}
+	return &taskResp.Data, nil
+}
+
+// WaitMassEditTask 轮询任务直到完成、失败或 ctx 结束，其他状态(包括未知状态)继续轮询
+func (c *Client) WaitMassEditTask(ctx context.Context, cookies, shopId, region, taskId string, interval time.Duration) (*MassEditTask, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		task, err := c.GetMassEditTask(cookies, shopId, region, taskId)
+		if err != nil {
+			logger.Warn("查询批量编辑任务失败", zap.String("task_id", taskId), zap.Error(err))
+		} else if task.Status == MassEditTaskFinished || task.Status == MassEditTaskFailed {
+			return task, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return task, fmt.Errorf("等待批量编辑任务 %s 超时: %w", taskId, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// downloadMassEditResult 下载结果文件，相对地址按 baseURL 拼接
+func (c *Client) downloadMassEditResult(ctx context.Context, cookies, fileURL string) ([]byte, error) {
+	if !strings.HasPrefix(fileURL, "http://") && !strings.HasPrefix(fileURL, "https://") {
+		fileURL = c.baseURL + fileURL
+	}
+	req, err := http.NewRequestWithContext(ctx, HTTPMethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header.Set("Cookie", cookies)
+	c.setCommonHeaders(req)
+
+	resp, err := c.executeWithRetry(req)
+	if err != nil {
+		return nil, fmt.Errorf("download result file failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download result file failed, status code: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// MassEditProducts 生成批量编辑模板并上传，等待任务完成后解析结果文件
+// 结果文件中没有出现的商品在任务完成时视为成功，任务失败时视为失败
+func (c *Client) MassEditProducts(ctx context.Context, cookies, shopId, region string,
+	changes []MassEditChange, opts MassEditOptions) (*MassEditReport, error) {
+	if len(changes) == 0 {
+		return &MassEditReport{}, nil
+	}
+	if opts.Dir == "" {
+		opts.Dir = os.TempDir()
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+
+	var buf bytes.Buffer
+	if err := BuildMassEditTemplate(&buf, changes); err != nil {
+		return nil, err
+	}
+	filename := filepath.Join(opts.Dir, utils.GenerateFileName(shopId))
+	if err := os.WriteFile(filename, buf.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("写入模板文件失败: %w", err)
+	}
+	if !opts.KeepFile {
+		defer os.Remove(filename)
+	}
+
+	taskId, err := c.UploadMassEditFile(UpdateProductInfoReq{Cookies: cookies, ShopID: shopId, Region: region}, filename)
+	if err != nil {
+		return nil, err
+	}
+	report := &MassEditReport{TaskId: taskId}
+	if taskId == "" {
+		return report, fmt.Errorf("上传成功但未返回任务 id，无法跟踪结果")
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	task, err := c.WaitMassEditTask(waitCtx, cookies, shopId, region, taskId, opts.PollInterval)
+	report.Task = task
+	if err != nil {
+		return report, err
+	}
+	if task.Status == MassEditTaskFailed && task.ResultFileURL == "" {
+		return report, fmt.Errorf("批量编辑任务 %s 失败", taskId)
+	}
+
+	failed := make(map[int64]bool)
+	reported := make(map[int64]bool)
+	if task.ResultFileURL != "" {
+		data, err := c.downloadMassEditResult(ctx, cookies, task.ResultFileURL)
+		if err != nil {
+			return report, err
+		}
+		rows, err := ParseMassEditResult(data)
+		if err != nil {
+			return report, err
+		}
+		for _, row := range rows {
+			reported[row.ProductId] = true
+			if !row.Success {
+				failed[row.ProductId] = true
+				report.Failed = append(report.Failed, row)
+			}
+		}
+	}
+	for _, change := range changes {
+		switch {
+		case failed[change.ProductId]:
+		case reported[change.ProductId] || task.Status == MassEditTaskFinished:
+			report.Succeeded = append(report.Succeeded, change.ProductId)
+		default:
+			report.Failed = append(report.Failed, MassEditRowResult{ProductId: change.ProductId, Reason: "批量编辑任务失败"})
+		}
+	}
+
+	logger.Info("批量编辑任务完成", zap.String("shop_id", shopId), zap.String("task_id", taskId),
+		zap.Int("succeeded", len(report.Succeeded)), zap.Int("failed", len(report.Failed)))
+	return report, nil
+}
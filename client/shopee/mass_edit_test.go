@@ -0,0 +1,135 @@
+package shopee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/pkg/xlsx"
+)
+
+func TestMassEditProducts(t *testing.T) {
+	var uploaded [][]string
+	polls := 0
+
+	var result bytes.Buffer
+	if err := xlsx.Write(&result, "Result", [][]string{
+		{MassEditColumnProductId, MassEditColumnDaysToShip, MassEditColumnFailReason},
+		{"1001", "7", ""},
+		{"1002", "7", "invalid days_to_ship"},
+	}); err != nil {
+		t.Fatalf("build result file: %v", err)
+	}
+
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPathBatchUpdateProductInfoWithFile:
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("form file: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			if uploaded, err = xlsx.ReadBytes(data); err != nil {
+				t.Errorf("read uploaded template: %v", err)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "data": map[string]string{"task_id": "task-1"}})
+		case APIPathMassUploadTask:
+			if r.URL.Query().Get("task_id") != "task-1" {
+				t.Errorf("unexpected task_id %s", r.URL.Query().Get("task_id"))
+			}
+			polls++
+			task := MassEditTask{TaskId: "task-1", Status: MassEditTaskProcessing}
+			if polls > 1 {
+				task.Status = MassEditTaskFinished
+				task.ResultFileURL = "/download/result.xlsx"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "data": task})
+		case "/download/result.xlsx":
+			w.Write(result.Bytes())
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	unlisted := false
+	changes := []MassEditChange{
+		{ProductId: 1001, DaysToShip: 7},
+		{ProductId: 1002, DaysToShip: 7},
+		{ProductId: 1003, DaysToShip: 2, Unlisted: &unlisted},
+	}
+	report, err := client.MassEditProducts(context.Background(), "cookie", "123", "TW", changes,
+		MassEditOptions{Dir: t.TempDir(), PollInterval: 10 * time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("MassEditProducts: %v", err)
+	}
+
+	if len(uploaded) != 5 || uploaded[4][0] != "1003" || uploaded[4][1] != "N" || uploaded[4][3] != "N" {
+		t.Errorf("unexpected template rows %v", uploaded)
+	}
+	if uploaded[2][1] != "Y" || uploaded[2][2] != "7" {
+		t.Errorf("days_to_ship 7 should be pre-order, got %v", uploaded[2])
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls, got %d", polls)
+	}
+	if report.TaskId != "task-1" || len(report.Succeeded) != 2 || len(report.Failed) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Failed[0].ProductId != 1002 || report.Failed[0].Reason != "invalid days_to_ship" {
+		t.Errorf("unexpected failure %+v", report.Failed[0])
+	}
+}
+
+func TestMassEditProductsFailedTask(t *testing.T) {
+	statuses := []int{MassEditTaskProcessing, 0, MassEditTaskFailed}
+	polls := 0
+
+	var result bytes.Buffer
+	if err := xlsx.Write(&result, "Result", [][]string{
+		{MassEditColumnProductId, MassEditColumnDaysToShip, MassEditColumnFailReason},
+		{"1001", "7", ""},
+	}); err != nil {
+		t.Fatalf("build result file: %v", err)
+	}
+
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPathBatchUpdateProductInfoWithFile:
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "data": map[string]string{"task_id": "task-1"}})
+		case APIPathMassUploadTask:
+			task := MassEditTask{TaskId: "task-1", Status: statuses[polls]}
+			if task.Status == MassEditTaskFailed {
+				task.ResultFileURL = "/download/result.xlsx"
+			}
+			polls++
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "data": task})
+		case "/download/result.xlsx":
+			w.Write(result.Bytes())
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	changes := []MassEditChange{
+		{ProductId: 1001, DaysToShip: 7},
+		{ProductId: 1002, DaysToShip: 7},
+	}
+	report, err := client.MassEditProducts(context.Background(), "cookie", "123", "TW", changes,
+		MassEditOptions{Dir: t.TempDir(), PollInterval: 10 * time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("MassEditProducts: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("unknown status should keep polling, got %d polls", polls)
+	}
+	if len(report.Succeeded) != 1 || report.Succeeded[0] != 1001 || len(report.Failed) != 1 || report.Failed[0].ProductId != 1002 {
+		t.Errorf("rows missing from a failed task should fail, got %+v", report)
+	}
+}
@@ -0,0 +1,139 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+)
+
+// newPreOrderInfo 出货天数为 2 天时为非预售，其余为预售
+func newPreOrderInfo(daysToShip int) PreOrderInfo {
+	return PreOrderInfo{
+		PreOrder:   daysToShip != 2,
+		DaysToShip: daysToShip,
+	}
+}
+
+// newModelPreOrderInfoList 将规格出货天数转换为请求参数
+func newModelPreOrderInfoList(changes []ModelDaysToShip) []ModelPreOrderInfo {
+	modelList := make([]ModelPreOrderInfo, 0, len(changes))
+	for _, change := range changes {
+		modelList = append(modelList, ModelPreOrderInfo{
+			ID:           change.ModelId,
+			PreOrderInfo: newPreOrderInfo(change.DaysToShip),
+		})
+	}
+	return modelList
+}
+
+// ValidateModelDaysToShip 校验规格出货天数：规格必须属于该商品，且不能重复
+func ValidateModelDaysToShip(productId int64, models []Model, changes []ModelDaysToShip) error {
+	if len(changes) == 0 {
+		return NewValidationError(fmt.Sprintf("商品 %d 没有需要修改的规格", productId))
+	}
+	owned := make(map[int64]bool, len(models))
+	for _, model := range models {
+		owned[int64(model.ID)] = true
+	}
+	seen := make(map[int64]bool, len(changes))
+	for _, change := range changes {
+		if change.DaysToShip <= 0 {
+			return NewValidationError(fmt.Sprintf("商品 %d 规格 %d 的出货天数必须大于 0", productId, change.ModelId))
+		}
+		if !owned[change.ModelId] {
+			return NewValidationError(fmt.Sprintf("规格 %d 不属于商品 %d", change.ModelId, productId))
+		}
+		if seen[change.ModelId] {
+			return NewValidationError(fmt.Sprintf("商品 %d 规格 %d 重复", productId, change.ModelId))
+		}
+		seen[change.ModelId] = true
+	}
+	return nil
+}
+
+// getProductDefinitions 逐个获取待修改商品的完整定义，只请求涉及的商品，不下载整个商品列表
+func (c *Client) getProductDefinitions(ctx context.Context, cookies, shopId, region string,
+	productIds []int64) (map[int64]*ProductDefinition, error) {
+	products := make(map[int64]*ProductDefinition, len(productIds))
+	for _, productId := range productIds {
+		product, err := c.GetProductDefinition(ctx, cookies, shopId, region, productId)
+		if err != nil {
+			return nil, err
+		}
+		products[productId] = product
+	}
+	return products, nil
+}
+
+// validateProductModels 获取商品定义并逐个校验规格，返回商品定义供构造请求时读取上下架状态
+func (c *Client) validateProductModels(ctx context.Context, cookies, shopId, region string,
+	changes map[int64][]ModelDaysToShip) (map[int64]*ProductDefinition, error) {
+	productIds := sortedItemIds(changes)
+	products, err := c.getProductDefinitions(ctx, cookies, shopId, region, productIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, productId := range productIds {
+		models := make([]Model, 0, len(products[productId].ModelList))
+		for _, model := range products[productId].ModelList {
+			models = append(models, Model{ID: int(model.ID)})
+		}
+		if err := ValidateModelDaysToShip(productId, models, changes[productId]); err != nil {
+			return nil, err
+		}
+	}
+	return products, nil
+}
+
+// buildUpdateProductInfoRequest 构造单个商品的更新参数，modelDaysToShip 不为空时按规格修改并先校验规格归属
+func (c *Client) buildUpdateProductInfoRequest(updateProductInfoReq UpdateProductInfoReq,
+	modelDaysToShip []ModelDaysToShip) (*UpdateProductInfoRequest, error) {
+	req := &UpdateProductInfoRequest{
+		ProductID: updateProductInfoReq.ProductId,
+		IsDraft:   false,
+	}
+
+	switch {
+	case len(modelDaysToShip) > 0:
+		products, err := c.validateProductModels(context.Background(), updateProductInfoReq.Cookies,
+			updateProductInfoReq.ShopID, updateProductInfoReq.Region,
+			map[int64][]ModelDaysToShip{updateProductInfoReq.ProductId: modelDaysToShip})
+		if err != nil {
+			return nil, err
+		}
+		req.ProductInfo.EnableModelLevelDts = true
+		req.ProductInfo.ModelList = newModelPreOrderInfoList(modelDaysToShip)
+		req.ProductInfo.Unlisted = products[updateProductInfoReq.ProductId].Unlisted
+	case updateProductInfoReq.DaysToShip != 0:
+		req.ProductInfo.PreOrderInfo = newPreOrderInfo(updateProductInfoReq.DaysToShip)
+	default:
+		req.ProductInfo.Unlisted = updateProductInfoReq.ProductStatus.Unlisted
+	}
+	return req, nil
+}
+
+// BatchUpdateModelDaysToShip 使用 V3 接口按规格批量修改出货天数，changes 以商品 id 为键
+// 发送前获取涉及商品的定义并校验规格归属，任一商品校验失败则整批不发送
+// 请求带上商品当前的 unlisted，不会改变商品的上下架状态
+func (c *Client) BatchUpdateModelDaysToShip(updateProductInfoReq UpdateProductInfoReq,
+	changes map[int64][]ModelDaysToShip, source string) ([]BatchUpdateProductInfoRespItem, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	products, err := c.validateProductModels(context.Background(), updateProductInfoReq.Cookies,
+		updateProductInfoReq.ShopID, updateProductInfoReq.Region, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := sortedItemIds(changes)
+	batchUpdateProductInfoReq := make([]*BatchUpdateProductInfoItem, 0, len(productIds))
+	for _, productId := range productIds {
+		batchUpdateProductInfoReq = append(batchUpdateProductInfoReq, &BatchUpdateProductInfoItem{
+			ID:                  productId,
+			EnableModelLevelDts: true,
+			ModelList:           newModelPreOrderInfoList(changes[productId]),
+			Unlisted:            products[productId].Unlisted,
+		})
+	}
+	return c.postBatchUpdateProductInfo(updateProductInfoReq, source, batchUpdateProductInfoReq)
+}
@@ -0,0 +1,64 @@
+package shopee
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBatchUpdateModelDaysToShip(t *testing.T) {
+	var sent []BatchUpdateProductInfoItem
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPathGetProductInfo:
+			product := ProductDefinition{ID: 1, ModelList: []ProductModelInfo{{ID: 11}, {ID: 12}}}
+			if r.URL.Query().Get("product_id") == "2" {
+				product = ProductDefinition{ID: 2, ModelList: []ProductModelInfo{{ID: 21}}, Unlisted: true}
+			}
+			json.NewEncoder(w).Encode(CommonResponse[GetProductInfoData]{Data: GetProductInfoData{ProductInfo: product}})
+		case APIPathBatchUpdateProductInfo:
+			if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+				t.Errorf("decode batch request: %v", err)
+			}
+			json.NewEncoder(w).Encode(BatchUpdateProductInfoResponse{})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	req := UpdateProductInfoReq{Cookies: "cookie", ShopID: "123", Region: "TW"}
+
+	_, err := client.BatchUpdateModelDaysToShip(req, map[int64][]ModelDaysToShip{
+		1: {{ModelId: 11, DaysToShip: 7}},
+		2: {{ModelId: 11, DaysToShip: 7}},
+	}, SourceSellerCenter)
+	var shopeeErr *ShopeeError
+	if !errors.As(err, &shopeeErr) || shopeeErr.Type != ErrTypeValidation {
+		t.Fatalf("model of another product should fail validation, got %v", err)
+	}
+	if sent != nil {
+		t.Fatalf("nothing should be sent when validation fails, got %+v", sent)
+	}
+
+	_, err = client.BatchUpdateModelDaysToShip(req, map[int64][]ModelDaysToShip{
+		2: {{ModelId: 21, DaysToShip: 2}},
+		1: {{ModelId: 11, DaysToShip: 7}, {ModelId: 12, DaysToShip: 10}},
+	}, SourceSellerCenter)
+	if err != nil {
+		t.Fatalf("BatchUpdateModelDaysToShip: %v", err)
+	}
+	if len(sent) != 2 || sent[0].ID != 1 || !sent[0].EnableModelLevelDts || len(sent[0].ModelList) != 2 {
+		t.Fatalf("unexpected batch request %+v", sent)
+	}
+	if sent[0].Unlisted || !sent[1].Unlisted {
+		t.Errorf("listing status should be kept, got %+v", sent)
+	}
+	if got := sent[1].ModelList[0].PreOrderInfo; got.PreOrder || got.DaysToShip != 2 {
+		t.Errorf("2 days should not be pre-order, got %+v", got)
+	}
+	if got := sent[0].ModelList[1].PreOrderInfo; !got.PreOrder || got.DaysToShip != 10 {
+		t.Errorf("unexpected model pre-order info %+v", got)
+	}
+}
@@ -0,0 +1,225 @@
+// Open Platform v2 签名请求
+package shopee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// OpenAPISignType v2 接口签名类型
+type OpenAPISignType int
+
+const (
+	// OpenAPISignPublic 公共接口: partner_id + path + timestamp
+	OpenAPISignPublic OpenAPISignType = iota
+	// OpenAPISignShop 店铺接口: partner_id + path + timestamp + access_token + shop_id
+	OpenAPISignShop
+	// OpenAPISignMerchant 商户接口: partner_id + path + timestamp + access_token + merchant_id
+	OpenAPISignMerchant
+)
+
+// OpenAPIEndpoint 声明一个 v2 接口
+type OpenAPIEndpoint struct {
+	Method   string
+	Path     string
+	SignType OpenAPISignType
+}
+
+// Open Platform v2 接口声明，新增接口只需在此处声明并定义响应类型
+var (
+	OpenAPIAuthToken       = OpenAPIEndpoint{HTTPMethodPost, APIPathAuthTokenForTw, OpenAPISignPublic}
+	OpenAPIAccessToken     = OpenAPIEndpoint{HTTPMethodPost, APIPathAccessTokenForTw, OpenAPISignPublic}
+	OpenAPIGetItemList     = OpenAPIEndpoint{HTTPMethodGet, APIPathProductListForTw, OpenAPISignShop}
+	OpenAPIGetItemBaseInfo = OpenAPIEndpoint{HTTPMethodGet, APIPathGetBaseProductInfo, OpenAPISignShop}
+	OpenAPIUpdateItem      = OpenAPIEndpoint{HTTPMethodPost, APIPathProductUpdateForTw, OpenAPISignShop}
+	OpenAPIUnlistItem      = OpenAPIEndpoint{HTTPMethodPost, APIPathUnlistItemForTw, OpenAPISignShop}
+	OpenAPIDeleteItem      = OpenAPIEndpoint{HTTPMethodPost, APIPathDeleteItemForTw, OpenAPISignShop}
+)
+
+// OpenAPIAuth 店铺或商户级接口的授权信息
+type OpenAPIAuth struct {
+	AccessToken string
+	ShopId      string
+	MerchantId  string
+}
+
+// OpenAPIRequest v2 请求，公共参数(partner_id/timestamp/sign 等)由客户端补齐
+type OpenAPIRequest struct {
+	Endpoint OpenAPIEndpoint
+	Auth     OpenAPIAuth
+	Query    url.Values
+	Body     interface{}
+}
+
+// OpenAPIResponse v2 通用响应
+type OpenAPIResponse[T any] struct {
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Msg       string `json:"msg"`
+	Warning   string `json:"warning"`
+	RequestId string `json:"request_id"`
+	Response  T      `json:"response"`
+}
+
+// openAPIPartner 返回签名使用的 partner_id 与 partner_key，未显式配置时按环境选择
+func (c *Client) openAPIPartner() (string, string) {
+	if c.partnerId != "" && c.partnerKey != "" {
+		return c.partnerId, c.partnerKey
+	}
+	return c.openPlatformEnv.Partner()
+}
+
+// OpenPlatformEnv 返回客户端当前的 Open Platform 环境
+func (c *Client) OpenPlatformEnv() OpenPlatformEnv {
+	if c.openPlatformEnv == "" {
+		return OpenPlatformEnvLive
+	}
+	return c.openPlatformEnv
+}
+
+// BuildOpenAPIURL 构建带公共参数与签名的完整请求地址
+func (c *Client) BuildOpenAPIURL(req OpenAPIRequest, timestamp int64) (string, error) {
+	partnerId, partnerKey := c.openAPIPartner()
+	timestampStr := strconv.FormatInt(timestamp, 10)
+	endpoint := req.Endpoint
+
+	params := url.Values{}
+	if req.Query != nil {
+		params = copyURLValues(req.Query)
+	}
+	params.Set("partner_id", partnerId)
+	params.Set("timestamp", timestampStr)
+
+	var sign string
+	switch endpoint.SignType {
+	case OpenAPISignPublic:
+		sign = c.publicSign(endpoint.Path, timestampStr)
+	case OpenAPISignShop:
+		if req.Auth.AccessToken == "" || req.Auth.ShopId == "" {
+			return "", NewValidationError(fmt.Sprintf("%s 需要 access_token 和 shop_id", endpoint.Path))
+		}
+		sign = c.shopSign(endpoint.Path, timestampStr, req.Auth.AccessToken, req.Auth.ShopId)
+		params.Set("access_token", req.Auth.AccessToken)
+		params.Set("shop_id", req.Auth.ShopId)
+	case OpenAPISignMerchant:
+		if req.Auth.AccessToken == "" || req.Auth.MerchantId == "" {
+			return "", NewValidationError(fmt.Sprintf("%s 需要 access_token 和 merchant_id", endpoint.Path))
+		}
+		sign = SignOpenAPI(partnerKey, partnerId, endpoint.Path, timestampStr, req.Auth.AccessToken, req.Auth.MerchantId)
+		params.Set("access_token", req.Auth.AccessToken)
+		params.Set("merchant_id", req.Auth.MerchantId)
+	default:
+		return "", NewValidationError(fmt.Sprintf("未知的签名类型: %d", endpoint.SignType))
+	}
+	params.Set("sign", sign)
+
+	return c.baseURL + endpoint.Path + "?" + params.Encode(), nil
+}
+
+// DoOpenAPIRequest 执行 v2 请求，校验响应中的 error 字段并返回原始响应体
+func (c *Client) DoOpenAPIRequest(ctx context.Context, req OpenAPIRequest) ([]byte, error) {
+	apiURL, err := c.BuildOpenAPIURL(req, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonData []byte
+	if req.Body != nil {
+		jsonData, err = json.Marshal(req.Body)
+		if err != nil {
+			return nil, NewParsingError("marshal request body failed", err)
+		}
+	}
+
+	resp, err := c.executeOpenAPIRequest(ctx, req.Endpoint.Method, apiURL, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, NewNetworkError("read response body failed", err)
+	}
+	logger.Debug("Body", zap.String("path", req.Endpoint.Path), zap.String("body:", string(body)))
+
+	var envelope OpenAPIResponse[json.RawMessage]
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		if httpErr := HandleHTTPError(resp, body); httpErr != nil {
+			return nil, httpErr
+		}
+		return nil, NewParsingError("unmarshal response failed", err)
+	}
+	if envelope.Error != "" {
+		return nil, NewOpenAPIError(envelope.Error, envelope.Message, envelope.RequestId, resp.StatusCode)
+	}
+	if envelope.Msg == RateLimitError {
+		return nil, NewRateLimitError(RateLimitError)
+	}
+	if httpErr := HandleHTTPError(resp, body); httpErr != nil {
+		return nil, httpErr
+	}
+	return body, nil
+}
+
+// DoOpenAPIRequestWithResponse 执行 v2 请求并解析 response 字段
+func DoOpenAPIRequestWithResponse[T any](c *Client, ctx context.Context, req OpenAPIRequest) (*T, error) {
+	body, err := c.DoOpenAPIRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp OpenAPIResponse[T]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, NewParsingError("unmarshal response failed", err)
+	}
+	if resp.Warning != "" {
+		logger.Warn("open api warning", zap.String("path", req.Endpoint.Path),
+			zap.String("warning", resp.Warning), zap.String("request_id", resp.RequestId))
+	}
+	return &resp.Response, nil
+}
+
+// executeOpenAPIRequest 执行请求，每次重试都重新构造请求体
+func (c *Client) executeOpenAPIRequest(ctx context.Context, method, apiURL string, jsonData []byte) (*http.Response, error) {
+	var lastErr error
+	for i := 0; i <= c.retryTimes; i++ {
+		var bodyReader io.Reader
+		if jsonData != nil {
+			bodyReader = bytes.NewReader(jsonData)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, apiURL, bodyReader)
+		if err != nil {
+			return nil, NewNetworkError("create request failed", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if i == c.retryTimes {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, NewNetworkError("request canceled", ctx.Err())
+		case <-time.After(c.retryDelay):
+		}
+	}
+	return nil, NewNetworkError(fmt.Sprintf("request failed after %d retries", c.retryTimes), lastErr)
+}
@@ -0,0 +1,349 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/pkg/constant"
+)
+
+// newOpenAPITestClient 创建指向本地测试服务的客户端
+func newOpenAPITestClient(handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.RetryTimes = 0
+	return NewClientWithConfig(config), server
+}
+
+func TestBuildOpenAPIURL(t *testing.T) {
+	client := NewTaiwanClient()
+
+	if _, err := client.BuildOpenAPIURL(OpenAPIRequest{Endpoint: OpenAPIGetItemList}, 1700000000); err == nil {
+		t.Error("shop level endpoint without access_token should fail")
+	}
+
+	apiURL, err := client.BuildOpenAPIURL(OpenAPIRequest{
+		Endpoint: OpenAPIGetItemList,
+		Auth:     OpenAPIAuth{AccessToken: "token", ShopId: "123"},
+	}, 1700000000)
+	if err != nil {
+		t.Fatalf("BuildOpenAPIURL() error = %v", err)
+	}
+	expectSign := GetShopSign(constant.PartnerId, APIPathProductListForTw, "1700000000", "token", "123")
+	req, _ := http.NewRequest(HTTPMethodGet, apiURL, nil)
+	query := req.URL.Query()
+	if query.Get("sign") != expectSign {
+		t.Errorf("Expected sign %s, got %s", expectSign, query.Get("sign"))
+	}
+	if query.Get("shop_id") != "123" || query.Get("partner_id") != constant.PartnerId {
+		t.Errorf("Unexpected common params: %v", query)
+	}
+}
+
+func TestDoOpenAPIRequest(t *testing.T) {
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Query().Get("shop_id") == "404":
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"error":"error_auth","message":"Invalid access_token.","request_id":"abc"}`)
+		case len(body) > 0:
+			io.WriteString(w, `{"error":"","message":"","request_id":"def","response":{"item_id":1,"pre_order":{"days_to_ship":7,"is_pre_order":true}}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+	})
+	defer server.Close()
+
+	data, err := DoOpenAPIRequestWithResponse[TWProductUpdateData](client, context.Background(), OpenAPIRequest{
+		Endpoint: OpenAPIUpdateItem,
+		Auth:     OpenAPIAuth{AccessToken: "token", ShopId: "1"},
+		Body:     UpdateProductInfoWithAreaTw{ItemId: 1},
+	})
+	if err != nil {
+		t.Fatalf("DoOpenAPIRequestWithResponse() error = %v", err)
+	}
+	if data.PreOrder.DaysToShip != 7 {
+		t.Errorf("Expected days_to_ship 7, got %d", data.PreOrder.DaysToShip)
+	}
+
+	_, err = client.DoOpenAPIRequest(context.Background(), OpenAPIRequest{
+		Endpoint: OpenAPIGetItemList,
+		Auth:     OpenAPIAuth{AccessToken: "token", ShopId: "404"},
+	})
+	shopeeErr, ok := err.(*ShopeeError)
+	if !ok {
+		t.Fatalf("Expected *ShopeeError, got %T", err)
+	}
+	if !shopeeErr.IsType(ErrTypeAuth) || shopeeErr.RequestID != "abc" {
+		t.Errorf("Unexpected error: %v", shopeeErr)
+	}
+}
+
+func TestOpenPlatformEnv(t *testing.T) {
+	sandbox := NewOpenPlatformClient(OpenPlatformEnvSandbox)
+	if sandbox.baseURL != BaseTestSellerURLForTw {
+		t.Errorf("Expected sandbox base URL %s, got %s", BaseTestSellerURLForTw, sandbox.baseURL)
+	}
+
+	// 覆盖地址指向本地服务时仍使用测试环境的 partner
+	config := OpenPlatformConfig(OpenPlatformEnvSandbox)
+	config.BaseURL = "http://127.0.0.1:8080"
+	local := NewClientWithConfig(config)
+	apiURL, err := local.BuildOpenAPIURL(OpenAPIRequest{Endpoint: OpenAPIAuthToken}, 1700000000)
+	if err != nil {
+		t.Fatalf("BuildOpenAPIURL() error = %v", err)
+	}
+	req, _ := http.NewRequest(HTTPMethodGet, apiURL, nil)
+	query := req.URL.Query()
+	expectSign := SignOpenAPI(constant.TestPartnerKey, constant.TestPartnerId, APIPathAuthTokenForTw, "1700000000")
+	if query.Get("partner_id") != constant.TestPartnerId || query.Get("sign") != expectSign {
+		t.Errorf("Sandbox client should sign with test partner, got %v", query)
+	}
+	if sign := local.publicSign(APIPathAuthTokenForTw, "1700000000"); sign != expectSign {
+		t.Errorf("publicSign() = %s, want %s", sign, expectSign)
+	}
+
+	if _, err := ParseOpenPlatformEnv("staging"); err == nil {
+		t.Error("Unknown env should fail")
+	}
+}
+
+func TestGetItemListAndBaseInfoWithAreaTw(t *testing.T) {
+	var baseInfoCalls int
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case APIPathProductListForTw:
+			if len(query["item_status"]) != len(TWItemStatusAll) {
+				t.Errorf("Expected all item status, got %v", query["item_status"])
+			}
+			if query.Get("offset") == "0" {
+				io.WriteString(w, `{"response":{"item":[{"item_id":1,"item_status":"NORMAL"}],"has_next_page":true,"next_offset":1}}`)
+				return
+			}
+			io.WriteString(w, `{"response":{"item":[{"item_id":2,"item_status":"BANNED"}],"has_next_page":false}}`)
+		case APIPathGetBaseProductInfo:
+			baseInfoCalls++
+			io.WriteString(w, `{"response":{"item_list":[{"item_id":1,"pre_order":{"days_to_ship":3}}]}}`)
+		}
+	})
+	defer server.Close()
+
+	auth := OpenAPIAuth{AccessToken: "token", ShopId: "1"}
+	items, err := client.GetItemListWithAreaTw(context.Background(), auth, TWItemListFilter{UpdateTimeFrom: 100})
+	if err != nil {
+		t.Fatalf("GetItemListWithAreaTw() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("Expected 2 items across pages, got %d", len(items))
+	}
+
+	// next_offset 未前进时不应无限翻页
+	stuckClient, stuckServer := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"response":{"item":[{"item_id":1}],"has_next_page":true,"next_offset":0}}`)
+	})
+	defer stuckServer.Close()
+	if _, err := stuckClient.GetItemListWithAreaTw(context.Background(), auth, TWItemListFilter{}); err == nil {
+		t.Error("Expected error when next_offset does not advance")
+	}
+
+	itemIdList := make([]int64, 120)
+	if _, err := client.GetItemBaseInfoListWithAreaTw(context.Background(), auth, itemIdList); err != nil {
+		t.Fatalf("GetItemBaseInfoListWithAreaTw() error = %v", err)
+	}
+	if baseInfoCalls != 3 {
+		t.Errorf("Expected 3 batches for 120 items, got %d", baseInfoCalls)
+	}
+}
+
+func TestGetProductListWithAreaTw(t *testing.T) {
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "0" {
+			io.WriteString(w, `{"response":{"item":[{"item_id":1}],"has_next_page":true,"next_offset":1}}`)
+			return
+		}
+		// next_offset 未前进
+		io.WriteString(w, `{"response":{"item":[{"item_id":2}],"has_next_page":true,"next_offset":1}}`)
+	})
+	defer server.Close()
+
+	if _, err := client.GetProductListWithAreaTw("token", "1"); err == nil {
+		t.Error("Expected error when next_offset does not advance")
+	}
+}
+
+func TestBulkUpdatePreOrderWithAreaTw(t *testing.T) {
+	var mu sync.Mutex
+	daysToShip := map[string]int{"1": 7, "2": 3, "3": 3}
+	updateCalls := map[string]int{}
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case APIPathGetBaseProductInfo:
+			var list []string
+			for _, id := range strings.Split(r.URL.Query().Get("item_id_list"), ",") {
+				if days, ok := daysToShip[id]; ok {
+					list = append(list, fmt.Sprintf(`{"item_id":%s,"pre_order":{"is_pre_order":true,"days_to_ship":%d}}`, id, days))
+				}
+			}
+			io.WriteString(w, `{"response":{"item_list":[`+strings.Join(list, ",")+`]}}`)
+		case APIPathProductUpdateForTw:
+			var req UpdateProductInfoWithAreaTw
+			json.NewDecoder(r.Body).Decode(&req)
+			id := strconv.FormatInt(req.ItemId, 10)
+			updateCalls[id]++
+			// 商品 3 第一次更新不生效
+			if id != "3" || updateCalls[id] > 1 {
+				daysToShip[id] = req.PreOrder.DaysToShip
+			}
+			io.WriteString(w, `{"response":{}}`)
+		}
+	})
+	defer server.Close()
+
+	report, err := client.BulkUpdatePreOrderWithAreaTw(context.Background(), OpenAPIAuth{AccessToken: "token", ShopId: "1"},
+		[]int64{1, 2, 3, 4}, UpdateProductInfoWithAreaTwItem{DaysToShip: 7, IsPreOrder: true},
+		TWBulkPreOrderOptions{RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("BulkUpdatePreOrderWithAreaTw() error = %v", err)
+	}
+	if len(report.Unchanged) != 1 || report.Unchanged[0] != 1 {
+		t.Errorf("Expected item 1 unchanged, got %v", report.Unchanged)
+	}
+	if len(report.Updated) != 2 {
+		t.Errorf("Expected items 2 and 3 updated, got %v", report.Updated)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].ItemId != 4 {
+		t.Errorf("Expected item 4 failed, got %v", report.Failed)
+	}
+	if updateCalls["3"] != 2 {
+		t.Errorf("Expected item 3 retried once, got %d calls", updateCalls["3"])
+	}
+}
+
+func TestGetOrderListWithAreaTw(t *testing.T) {
+	var windows []string
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case APIPathOrderListForTw:
+			if query.Get("cursor") == "" {
+				windows = append(windows, query.Get("time_from")+"-"+query.Get("time_to"))
+				io.WriteString(w, `{"response":{"more":true,"next_cursor":"1","order_list":[{"order_sn":"A"}]}}`)
+				return
+			}
+			io.WriteString(w, `{"response":{"more":false,"order_list":[{"order_sn":"B"}]}}`)
+		case APIPathOrderDetailForTw:
+			sns := strings.Split(query.Get("order_sn_list"), ",")
+			io.WriteString(w, fmt.Sprintf(`{"response":{"order_list":[{"order_sn":%q}]}}`, sns[0]))
+		}
+	})
+	defer server.Close()
+
+	auth := OpenAPIAuth{AccessToken: "token", ShopId: "1"}
+	from := time.Unix(1700000000, 0)
+	orders, err := client.GetOrderListWithAreaTw(context.Background(), auth, TWOrderListFilter{
+		TimeFrom: from,
+		TimeTo:   from.Add(20 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("GetOrderListWithAreaTw() error = %v", err)
+	}
+	window1, window2 := fmt.Sprintf("%d-%d", from.Unix(), from.Add(TWOrderListMaxWindow).Unix()-1),
+		fmt.Sprintf("%d-%d", from.Add(TWOrderListMaxWindow).Unix(), from.Add(20*24*time.Hour).Unix())
+	if len(windows) != 2 || windows[0] != window1 || windows[1] != window2 {
+		t.Errorf("Expected 20 days split into 2 non-overlapping windows, got %v", windows)
+	}
+	if len(orders) != 4 {
+		t.Errorf("Expected 4 orders across windows and pages, got %d", len(orders))
+	}
+
+	for _, filter := range []TWOrderListFilter{
+		{TimeTo: from},
+		{TimeFrom: from, TimeTo: from.Add(TWOrderListMaxRange + time.Second)},
+	} {
+		if _, err := client.GetOrderListWithAreaTw(context.Background(), auth, filter); err == nil {
+			t.Errorf("Expected validation error for %+v", filter)
+		}
+	}
+
+	details, err := client.GetOrderDetailListWithAreaTw(context.Background(), auth, make([]string, 60))
+	if err != nil {
+		t.Fatalf("GetOrderDetailListWithAreaTw() error = %v", err)
+	}
+	if len(details) != 2 {
+		t.Errorf("Expected 2 batches for 60 orders, got %d", len(details))
+	}
+}
+
+func TestGetShippingParameterWithAreaTw(t *testing.T) {
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != APIPathShippingParameterForTw || query.Get("order_sn") != "A" || query.Get("package_number") != "P1" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		io.WriteString(w, `{"response":{"info_needed":{"dropoff":["branch_id"]},"dropoff":{"branch_list":[{"branch_id":7,"city":"台北市"}]}}}`)
+	})
+	defer server.Close()
+
+	auth := OpenAPIAuth{AccessToken: "token", ShopId: "1"}
+	if _, err := client.GetShippingParameterWithAreaTw(context.Background(), auth, "", ""); err == nil {
+		t.Error("Expected validation error for empty order_sn")
+	}
+	param, err := client.GetShippingParameterWithAreaTw(context.Background(), auth, "A", "P1")
+	if err != nil {
+		t.Fatalf("GetShippingParameterWithAreaTw() error = %v", err)
+	}
+	if len(param.InfoNeeded.Dropoff) != 1 || len(param.Dropoff.BranchList) != 1 || param.Dropoff.BranchList[0].BranchId != 7 {
+		t.Errorf("Unexpected shipping parameter %+v", param)
+	}
+}
+
+func TestBatchUpdatePriceWithAreaTw(t *testing.T) {
+	var requests []TWUpdatePriceReq
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		var req TWUpdatePriceReq
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		if req.ItemId == 2 {
+			io.WriteString(w, `{"error":"product.error_busi","message":"item is locked"}`)
+			return
+		}
+		io.WriteString(w, `{"response":{"success_list":[{"model_id":1}],"failure_list":[{"model_id":2,"failed_reason":"price too low"}]}}`)
+	})
+	defer server.Close()
+
+	changes := []TWPriceChange{{ItemId: 2, ModelId: 9, OriginalPrice: 10}}
+	for i := 0; i < 60; i++ {
+		changes = append(changes, TWPriceChange{ItemId: 1, ModelId: int64(i + 1), OriginalPrice: 10})
+	}
+	results, err := client.BatchUpdatePriceWithAreaTw(context.Background(), OpenAPIAuth{AccessToken: "token", ShopId: "1"}, changes)
+	if err != nil {
+		t.Fatalf("BatchUpdatePriceWithAreaTw() error = %v", err)
+	}
+	if len(requests) != 3 || len(requests[0].PriceList) != TWModelBatchSize {
+		t.Errorf("Expected item 1 split into 2 requests plus 1 for item 2, got %d", len(requests))
+	}
+	var failed int
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	// 每个成功请求各 1 个失败规格，item 2 整体失败
+	if len(results) != 5 || failed != 3 {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
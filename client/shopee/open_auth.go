@@ -0,0 +1,23 @@
+package shopee
+
+import (
+	"net/url"
+	"time"
+)
+
+// OpenAPIAuthPartner 店铺授权页面
+var OpenAPIAuthPartner = OpenAPIEndpoint{HTTPMethodGet, APIPathSignForTw, OpenAPISignPublic}
+
+// BuildAuthPartnerURL 生成店铺授权链接，卖家授权后 Shopee 会携带 code 与 shop_id 跳转到 redirectURL
+func (c *Client) BuildAuthPartnerURL(redirectURL string) (string, error) {
+	if redirectURL == "" {
+		return "", NewValidationError("redirect 地址不能为空")
+	}
+	if _, err := url.ParseRequestURI(redirectURL); err != nil {
+		return "", NewValidationError("redirect 地址格式错误: " + redirectURL)
+	}
+	return c.BuildOpenAPIURL(OpenAPIRequest{
+		Endpoint: OpenAPIAuthPartner,
+		Query:    url.Values{"redirect": {redirectURL}},
+	}, time.Now().Unix())
+}
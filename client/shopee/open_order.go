@@ -0,0 +1,161 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// 订单接口声明
+var (
+	OpenAPIGetOrderList         = OpenAPIEndpoint{HTTPMethodGet, APIPathOrderListForTw, OpenAPISignShop}
+	OpenAPIGetOrderDetail       = OpenAPIEndpoint{HTTPMethodGet, APIPathOrderDetailForTw, OpenAPISignShop}
+	OpenAPIGetShippingParameter = OpenAPIEndpoint{HTTPMethodGet, APIPathShippingParameterForTw, OpenAPISignShop}
+)
+
+// get_order_list 查询的时间字段
+const (
+	TWOrderTimeRangeCreateTime = "create_time"
+	TWOrderTimeRangeUpdateTime = "update_time"
+)
+
+const (
+	// TWOrderListMaxWindow get_order_list 单次查询的最大时间跨度
+	TWOrderListMaxWindow = 15 * 24 * time.Hour
+	// TWOrderListMaxRange 单次调用允许的最大时间跨度，超过时需调用方自行分段
+	TWOrderListMaxRange = 180 * 24 * time.Hour
+	// TWOrderListPageSize get_order_list 单页最大数量
+	TWOrderListPageSize = 100
+	// TWOrderDetailBatchSize get_order_detail 单次最多查询的订单数
+	TWOrderDetailBatchSize = 50
+)
+
+// TWOrderDetailOptionalFields get_order_detail 默认返回的可选字段
+var TWOrderDetailOptionalFields = []string{
+	"buyer_user_id", "buyer_username", "estimated_shipping_fee", "recipient_address", "item_list",
+	"pay_time", "total_amount", "shipping_carrier", "payment_method", "package_list",
+}
+
+// TWOrderListFilter get_order_list 查询条件，时间跨度超过 15 天时自动切分窗口
+type TWOrderListFilter struct {
+	TimeRangeField string    // create_time / update_time，默认 create_time
+	TimeFrom       time.Time // 必填
+	TimeTo         time.Time
+	OrderStatus    string // 为空时查询全部状态
+	PageSize       int
+}
+
+// GetOrderListWithAreaTw 按时间窗口与游标翻页获取订单列表
+func (c *Client) GetOrderListWithAreaTw(ctx context.Context, auth OpenAPIAuth, filter TWOrderListFilter) ([]TWOrderItem, error) {
+	if filter.TimeRangeField == "" {
+		filter.TimeRangeField = TWOrderTimeRangeCreateTime
+	}
+	if filter.TimeTo.IsZero() {
+		filter.TimeTo = time.Now()
+	}
+	if filter.TimeFrom.IsZero() {
+		return nil, NewValidationError("time_from 不能为空")
+	}
+	if !filter.TimeFrom.Before(filter.TimeTo) {
+		return nil, NewValidationError("time_from 必须早于 time_to")
+	}
+	if filter.TimeTo.Sub(filter.TimeFrom) > TWOrderListMaxRange {
+		return nil, NewValidationError(fmt.Sprintf("时间跨度不能超过 %d 天", int(TWOrderListMaxRange/(24*time.Hour))))
+	}
+	if filter.PageSize <= 0 || filter.PageSize > TWOrderListPageSize {
+		filter.PageSize = TWOrderListPageSize
+	}
+
+	var orders []TWOrderItem
+	for windowFrom := filter.TimeFrom; windowFrom.Before(filter.TimeTo); windowFrom = windowFrom.Add(TWOrderListMaxWindow) {
+		// time_from 与 time_to 都是闭区间，非最后一个窗口的结束时间减 1 秒，避免相邻窗口重复返回边界上的订单
+		windowTo := windowFrom.Add(TWOrderListMaxWindow).Add(-time.Second)
+		if !windowTo.Before(filter.TimeTo) {
+			windowTo = filter.TimeTo
+		}
+
+		query := url.Values{
+			"time_range_field":         {filter.TimeRangeField},
+			"time_from":                {strconv.FormatInt(windowFrom.Unix(), 10)},
+			"time_to":                  {strconv.FormatInt(windowTo.Unix(), 10)},
+			"page_size":                {strconv.Itoa(filter.PageSize)},
+			"response_optional_fields": {"order_status"},
+		}
+		if filter.OrderStatus != "" {
+			query.Set("order_status", filter.OrderStatus)
+		}
+
+		cursor := ""
+		for {
+			params := copyURLValues(query)
+			params.Set("cursor", cursor)
+
+			data, err := DoOpenAPIRequestWithResponse[TWOrderListData](c, ctx, OpenAPIRequest{
+				Endpoint: OpenAPIGetOrderList,
+				Auth:     auth,
+				Query:    params,
+			})
+			if err != nil {
+				return orders, fmt.Errorf("获取订单列表失败, time_from=%d, cursor=%s: %w", windowFrom.Unix(), cursor, err)
+			}
+			orders = append(orders, data.OrderList...)
+
+			if !data.More || data.NextCursor == "" {
+				break
+			}
+			cursor = data.NextCursor
+		}
+	}
+
+	logger.Info("订单列表获取完成", zap.String("shop_id", auth.ShopId),
+		zap.Time("time_from", filter.TimeFrom), zap.Time("time_to", filter.TimeTo), zap.Int("total", len(orders)))
+	return orders, nil
+}
+
+// GetOrderDetailListWithAreaTw 分批(每批 50 个)获取订单详情
+func (c *Client) GetOrderDetailListWithAreaTw(ctx context.Context, auth OpenAPIAuth, orderSnList []string) ([]TWOrderDetail, error) {
+	var orders []TWOrderDetail
+	for start := 0; start < len(orderSnList); start += TWOrderDetailBatchSize {
+		end := start + TWOrderDetailBatchSize
+		if end > len(orderSnList) {
+			end = len(orderSnList)
+		}
+
+		data, err := DoOpenAPIRequestWithResponse[TWOrderDetailListData](c, ctx, OpenAPIRequest{
+			Endpoint: OpenAPIGetOrderDetail,
+			Auth:     auth,
+			Query: url.Values{
+				"order_sn_list":            {strings.Join(orderSnList[start:end], ",")},
+				"response_optional_fields": {strings.Join(TWOrderDetailOptionalFields, ",")},
+			},
+		})
+		if err != nil {
+			return orders, fmt.Errorf("获取订单详情失败, batch=%d: %w", start/TWOrderDetailBatchSize, err)
+		}
+		orders = append(orders, data.OrderList...)
+	}
+	return orders, nil
+}
+
+// GetShippingParameterWithAreaTw 获取订单发货所需参数(揽收地址、寄件门店等)，packageNumber 可为空
+func (c *Client) GetShippingParameterWithAreaTw(ctx context.Context, auth OpenAPIAuth, orderSn, packageNumber string) (*TWShippingParameter, error) {
+	if orderSn == "" {
+		return nil, NewValidationError("order_sn 不能为空")
+	}
+	query := url.Values{"order_sn": {orderSn}}
+	if packageNumber != "" {
+		query.Set("package_number", packageNumber)
+	}
+	return DoOpenAPIRequestWithResponse[TWShippingParameter](c, ctx, OpenAPIRequest{
+		Endpoint: OpenAPIGetShippingParameter,
+		Auth:     auth,
+		Query:    query,
+	})
+}
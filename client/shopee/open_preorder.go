@@ -0,0 +1,177 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// TWBulkPreOrderOptions 批量更新预售信息的配置
+type TWBulkPreOrderOptions struct {
+	Concurrency int           // 并发更新数，默认 5
+	MaxRetries  int           // 校验未生效时的最大重试轮数，默认 2，小于 0 表示不重试
+	RetryDelay  time.Duration // 每轮重试前的等待时间，默认 2s
+}
+
+// TWPreOrderFailure 更新失败的商品
+type TWPreOrderFailure struct {
+	ItemId int64  `json:"item_id"`
+	Reason string `json:"reason"`
+}
+
+// TWPreOrderUpdateReport 批量更新预售信息的结果
+type TWPreOrderUpdateReport struct {
+	Updated   []int64             `json:"updated"`   // 已更新并校验通过
+	Unchanged []int64             `json:"unchanged"` // 更新前已是目标值
+	Failed    []TWPreOrderFailure `json:"failed"`
+}
+
+func (opts *TWBulkPreOrderOptions) setDefaults() {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 5
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = 2
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = 2 * time.Second
+	}
+}
+
+// preOrderMatches 判断商品当前的预售信息是否已是目标值，非预售且未指定出货天数时只比较预售标记
+func preOrderMatches(current ProductPreOrderInfoWithAreaTwItem, target UpdateProductInfoWithAreaTwItem) bool {
+	if current.IsPreOrder != target.IsPreOrder {
+		return false
+	}
+	if !target.IsPreOrder && target.DaysToShip == 0 {
+		return true
+	}
+	return current.DaysToShip == target.DaysToShip
+}
+
+// BulkUpdatePreOrderWithAreaTw 批量更新店铺商品的预售信息
+// 先读取当前值跳过已达标的商品，再并发更新，随后通过 get_item_base_info 分批校验，未生效或可重试失败的商品会重试
+func (c *Client) BulkUpdatePreOrderWithAreaTw(ctx context.Context, auth OpenAPIAuth, itemIdList []int64,
+	target UpdateProductInfoWithAreaTwItem, opts TWBulkPreOrderOptions) (*TWPreOrderUpdateReport, error) {
+	opts.setDefaults()
+	report := &TWPreOrderUpdateReport{}
+
+	currentList, err := c.GetItemBaseInfoListWithAreaTw(ctx, auth, itemIdList)
+	if err != nil {
+		return nil, fmt.Errorf("获取商品当前预售信息失败: %w", err)
+	}
+	currentMap := make(map[int64]ProductPreOrderInfoWithAreaTwItem, len(currentList))
+	for _, item := range currentList {
+		currentMap[item.ItemId] = item.PreOrder
+	}
+
+	var pending []int64
+	for _, itemId := range itemIdList {
+		current, ok := currentMap[itemId]
+		switch {
+		case !ok:
+			report.Failed = append(report.Failed, TWPreOrderFailure{ItemId: itemId, Reason: "商品不存在"})
+		case preOrderMatches(current, target):
+			report.Unchanged = append(report.Unchanged, itemId)
+		default:
+			pending = append(pending, itemId)
+		}
+	}
+
+	lastReason := make(map[int64]string)
+	for attempt := 0; attempt <= opts.MaxRetries && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			case <-time.After(opts.RetryDelay):
+			}
+		}
+
+		var sent []int64
+		var retry []int64
+		updateErrs := c.updatePreOrderConcurrently(ctx, auth, pending, target, opts.Concurrency)
+		for _, itemId := range pending {
+			updateErr := updateErrs[itemId]
+			if updateErr == nil {
+				sent = append(sent, itemId)
+				continue
+			}
+			lastReason[itemId] = updateErr.Error()
+			if IsRetryableError(updateErr) {
+				retry = append(retry, itemId)
+			} else {
+				report.Failed = append(report.Failed, TWPreOrderFailure{ItemId: itemId, Reason: updateErr.Error()})
+			}
+		}
+
+		verified, err := c.GetItemBaseInfoListWithAreaTw(ctx, auth, sent)
+		if err != nil {
+			// 校验失败时无法确认结果，下一轮重新更新
+			logger.Error("校验预售信息失败", zap.String("shop_id", auth.ShopId), zap.Error(err))
+			for _, itemId := range sent {
+				lastReason[itemId] = "校验失败: " + err.Error()
+			}
+			pending = append(retry, sent...)
+			continue
+		}
+		verifiedMap := make(map[int64]ProductPreOrderInfoWithAreaTwItem, len(verified))
+		for _, item := range verified {
+			verifiedMap[item.ItemId] = item.PreOrder
+		}
+		for _, itemId := range sent {
+			if current, ok := verifiedMap[itemId]; ok && preOrderMatches(current, target) {
+				report.Updated = append(report.Updated, itemId)
+				continue
+			}
+			lastReason[itemId] = "更新未生效"
+			retry = append(retry, itemId)
+		}
+		pending = retry
+
+		logger.Info("预售信息批量更新", zap.String("shop_id", auth.ShopId), zap.Int("attempt", attempt+1),
+			zap.Int("updated", len(report.Updated)), zap.Int("pending", len(pending)))
+	}
+
+	for _, itemId := range pending {
+		report.Failed = append(report.Failed, TWPreOrderFailure{ItemId: itemId, Reason: lastReason[itemId]})
+	}
+	return report, nil
+}
+
+// updatePreOrderConcurrently 以有限并发更新商品预售信息，返回每个商品的更新错误
+func (c *Client) updatePreOrderConcurrently(ctx context.Context, auth OpenAPIAuth, itemIdList []int64,
+	target UpdateProductInfoWithAreaTwItem, concurrency int) map[int64]error {
+	results := make(map[int64]error, len(itemIdList))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, itemId := range itemIdList {
+		itemId := itemId
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := DoOpenAPIRequestWithResponse[TWProductUpdateData](c, ctx, OpenAPIRequest{
+				Endpoint: OpenAPIUpdateItem,
+				Auth:     auth,
+				Body:     UpdateProductInfoWithAreaTw{ItemId: itemId, PreOrder: target},
+			})
+			mu.Lock()
+			results[itemId] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
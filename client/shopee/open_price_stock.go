@@ -0,0 +1,170 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// 价格与库存接口声明
+var (
+	OpenAPIUpdatePrice = OpenAPIEndpoint{HTTPMethodPost, APIPathUpdatePriceForTw, OpenAPISignShop}
+	OpenAPIUpdateStock = OpenAPIEndpoint{HTTPMethodPost, APIPathUpdateStockForTw, OpenAPISignShop}
+)
+
+// TWModelBatchSize update_price/update_stock 单次请求最多包含的规格数
+const TWModelBatchSize = 50
+
+// TWPriceChange 规格价格变更，无规格商品 ModelId 为 0
+type TWPriceChange struct {
+	ItemId        int64
+	ModelId       int64
+	OriginalPrice float64
+}
+
+// TWStockChange 规格库存变更，无规格商品 ModelId 为 0
+type TWStockChange struct {
+	ItemId     int64
+	ModelId    int64
+	LocationId string // 多仓店铺需指定仓库
+	Stock      int
+}
+
+// TWModelUpdateResult 规格更新结果
+type TWModelUpdateResult struct {
+	ItemId  int64  `json:"item_id"`
+	ModelId int64  `json:"model_id"`
+	Success bool   `json:"success"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// UpdatePriceWithAreaTw 更新单个商品的规格价格，返回每个规格的结果
+func (c *Client) UpdatePriceWithAreaTw(ctx context.Context, auth OpenAPIAuth, itemId int64, priceList []TWModelPrice) ([]TWModelUpdateResult, error) {
+	if len(priceList) == 0 {
+		return nil, nil
+	}
+	if len(priceList) > TWModelBatchSize {
+		return nil, NewValidationError(fmt.Sprintf("单次最多更新 %d 个规格", TWModelBatchSize))
+	}
+	data, err := DoOpenAPIRequestWithResponse[TWModelUpdateData](c, ctx, OpenAPIRequest{
+		Endpoint: OpenAPIUpdatePrice,
+		Auth:     auth,
+		Body:     TWUpdatePriceReq{ItemId: itemId, PriceList: priceList},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.results(itemId), nil
+}
+
+// UpdateStockWithAreaTw 更新单个商品的规格库存，返回每个规格的结果
+func (c *Client) UpdateStockWithAreaTw(ctx context.Context, auth OpenAPIAuth, itemId int64, stockList []TWModelStock) ([]TWModelUpdateResult, error) {
+	if len(stockList) == 0 {
+		return nil, nil
+	}
+	if len(stockList) > TWModelBatchSize {
+		return nil, NewValidationError(fmt.Sprintf("单次最多更新 %d 个规格", TWModelBatchSize))
+	}
+	data, err := DoOpenAPIRequestWithResponse[TWModelUpdateData](c, ctx, OpenAPIRequest{
+		Endpoint: OpenAPIUpdateStock,
+		Auth:     auth,
+		Body:     TWUpdateStockReq{ItemId: itemId, StockList: stockList},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.results(itemId), nil
+}
+
+// BatchUpdatePriceWithAreaTw 按商品分组并按接口上限切分后更新价格
+// 单个请求失败时该请求内的规格全部记为失败，不影响其他请求
+func (c *Client) BatchUpdatePriceWithAreaTw(ctx context.Context, auth OpenAPIAuth, changes []TWPriceChange) ([]TWModelUpdateResult, error) {
+	grouped := make(map[int64][]TWModelPrice)
+	for _, change := range changes {
+		grouped[change.ItemId] = append(grouped[change.ItemId], TWModelPrice{
+			ModelId:       change.ModelId,
+			OriginalPrice: change.OriginalPrice,
+		})
+	}
+
+	var results []TWModelUpdateResult
+	for _, itemId := range sortedItemIds(grouped) {
+		priceList := grouped[itemId]
+		for start := 0; start < len(priceList); start += TWModelBatchSize {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			batch := priceList[start:minInt(start+TWModelBatchSize, len(priceList))]
+			batchResults, err := c.UpdatePriceWithAreaTw(ctx, auth, itemId, batch)
+			if err != nil {
+				for _, price := range batch {
+					results = append(results, TWModelUpdateResult{ItemId: itemId, ModelId: price.ModelId, Reason: err.Error()})
+				}
+				continue
+			}
+			results = append(results, batchResults...)
+		}
+	}
+	return results, nil
+}
+
+// BatchUpdateStockWithAreaTw 按商品分组并按接口上限切分后更新库存
+// 单个请求失败时该请求内的规格全部记为失败，不影响其他请求
+func (c *Client) BatchUpdateStockWithAreaTw(ctx context.Context, auth OpenAPIAuth, changes []TWStockChange) ([]TWModelUpdateResult, error) {
+	grouped := make(map[int64][]TWModelStock)
+	for _, change := range changes {
+		grouped[change.ItemId] = append(grouped[change.ItemId], TWModelStock{
+			ModelId:     change.ModelId,
+			SellerStock: []TWSellerStock{{LocationId: change.LocationId, Stock: change.Stock}},
+		})
+	}
+
+	var results []TWModelUpdateResult
+	for _, itemId := range sortedItemIds(grouped) {
+		stockList := grouped[itemId]
+		for start := 0; start < len(stockList); start += TWModelBatchSize {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			batch := stockList[start:minInt(start+TWModelBatchSize, len(stockList))]
+			batchResults, err := c.UpdateStockWithAreaTw(ctx, auth, itemId, batch)
+			if err != nil {
+				for _, stock := range batch {
+					results = append(results, TWModelUpdateResult{ItemId: itemId, ModelId: stock.ModelId, Reason: err.Error()})
+				}
+				continue
+			}
+			results = append(results, batchResults...)
+		}
+	}
+	return results, nil
+}
+
+// results 将 success_list/failure_list 转换为规格结果
+func (d *TWModelUpdateData) results(itemId int64) []TWModelUpdateResult {
+	results := make([]TWModelUpdateResult, 0, len(d.SuccessList)+len(d.FailureList))
+	for _, item := range d.SuccessList {
+		results = append(results, TWModelUpdateResult{ItemId: itemId, ModelId: item.ModelId, Success: true})
+	}
+	for _, item := range d.FailureList {
+		results = append(results, TWModelUpdateResult{ItemId: itemId, ModelId: item.ModelId, Reason: item.FailedReason})
+	}
+	return results
+}
+
+// sortedItemIds 按商品 id 排序，保证请求顺序稳定
+func sortedItemIds[T any](grouped map[int64]T) []int64 {
+	itemIds := make([]int64, 0, len(grouped))
+	for itemId := range grouped {
+		itemIds = append(itemIds, itemId)
+	}
+	sort.Slice(itemIds, func(i, j int) bool { return itemIds[i] < itemIds[j] })
+	return itemIds
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
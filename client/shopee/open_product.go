@@ -0,0 +1,171 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// tw 商品状态
+const (
+	TWItemStatusNormal    = "NORMAL"
+	TWItemStatusUnlist    = "UNLIST"
+	TWItemStatusBanned    = "BANNED"
+	TWItemStatusReviewing = "REVIEWING"
+)
+
+const (
+	// TWItemUpdateTimeEpoch 全量扫描时 update_time_from 的起点
+	TWItemUpdateTimeEpoch = 1264143919
+	// TWItemListPageSize get_item_list 单页最大数量
+	TWItemListPageSize = 100
+	// TWItemBaseInfoBatchSize get_item_base_info 单次最多查询的商品数
+	TWItemBaseInfoBatchSize = 50
+)
+
+// TWItemStatusAll 增量同步关注的全部商品状态
+var TWItemStatusAll = []string{TWItemStatusNormal, TWItemStatusUnlist, TWItemStatusBanned, TWItemStatusReviewing}
+
+// TWItemListFilter get_item_list 查询条件
+type TWItemListFilter struct {
+	UpdateTimeFrom int64
+	UpdateTimeTo   int64
+	ItemStatus     []string
+	PageSize       int
+}
+
+// GetItemListWithAreaTw 按更新时间与状态翻页获取商品列表
+func (c *Client) GetItemListWithAreaTw(ctx context.Context, auth OpenAPIAuth, filter TWItemListFilter) ([]TWProductItem, error) {
+	if filter.UpdateTimeFrom <= 0 {
+		filter.UpdateTimeFrom = TWItemUpdateTimeEpoch
+	}
+	if filter.UpdateTimeTo <= 0 {
+		filter.UpdateTimeTo = time.Now().Unix()
+	}
+	if len(filter.ItemStatus) == 0 {
+		filter.ItemStatus = TWItemStatusAll
+	}
+	if filter.PageSize <= 0 || filter.PageSize > TWItemListPageSize {
+		filter.PageSize = TWItemListPageSize
+	}
+
+	query := url.Values{
+		"item_status":      filter.ItemStatus,
+		"page_size":        {strconv.Itoa(filter.PageSize)},
+		"update_time_from": {strconv.FormatInt(filter.UpdateTimeFrom, 10)},
+		"update_time_to":   {strconv.FormatInt(filter.UpdateTimeTo, 10)},
+	}
+
+	var items []TWProductItem
+	hasNextPage := true
+	offset := int64(0)
+	for hasNextPage {
+		params := copyURLValues(query)
+		params.Set("offset", strconv.FormatInt(offset, 10))
+
+		data, err := DoOpenAPIRequestWithResponse[TWProductListData](c, ctx, OpenAPIRequest{
+			Endpoint: OpenAPIGetItemList,
+			Auth:     auth,
+			Query:    params,
+		})
+		if err != nil {
+			return items, fmt.Errorf("获取商品列表失败, offset=%d: %w", offset, err)
+		}
+		items = append(items, data.Items...)
+
+		if err := data.checkNextOffset(offset); err != nil {
+			return items, fmt.Errorf("获取商品列表失败: %w", err)
+		}
+		hasNextPage = data.HasNextPage
+		offset = data.NextOffset
+	}
+
+	logger.Info("商品列表获取完成", zap.String("shop_id", auth.ShopId),
+		zap.Int64("update_time_from", filter.UpdateTimeFrom), zap.Int("total", len(items)))
+	return items, nil
+}
+
+// GetItemBaseInfoListWithAreaTw 分批(每批 50 个)获取商品基础信息
+func (c *Client) GetItemBaseInfoListWithAreaTw(ctx context.Context, auth OpenAPIAuth, itemIdList []int64) ([]ProductBaseInfoWithAreaTw, error) {
+	var itemList []ProductBaseInfoWithAreaTw
+	for start := 0; start < len(itemIdList); start += TWItemBaseInfoBatchSize {
+		end := start + TWItemBaseInfoBatchSize
+		if end > len(itemIdList) {
+			end = len(itemIdList)
+		}
+
+		itemIDStrs := make([]string, 0, end-start)
+		for _, id := range itemIdList[start:end] {
+			itemIDStrs = append(itemIDStrs, strconv.FormatInt(id, 10))
+		}
+
+		data, err := DoOpenAPIRequestWithResponse[ProductBaseInfoListWithAreaTw](c, ctx, OpenAPIRequest{
+			Endpoint: OpenAPIGetItemBaseInfo,
+			Auth:     auth,
+			Query:    url.Values{"item_id_list": {strings.Join(itemIDStrs, ",")}},
+		})
+		if err != nil {
+			return itemList, fmt.Errorf("获取商品基础信息失败, batch=%d: %w", start/TWItemBaseInfoBatchSize, err)
+		}
+		itemList = append(itemList, data.ItemList...)
+	}
+	return itemList, nil
+}
+
+// TWUnlistItemBatchSize unlist_item 单次最多处理的商品数
+const TWUnlistItemBatchSize = 50
+
+// TWItemFailure 商品操作失败信息
+type TWItemFailure struct {
+	ItemId int64  `json:"item_id"`
+	Reason string `json:"reason"`
+}
+
+// UnlistItemsWithAreaTw 分批上架(unlist=false)或下架(unlist=true)商品，返回成功的商品与失败信息
+func (c *Client) UnlistItemsWithAreaTw(ctx context.Context, auth OpenAPIAuth, itemIdList []int64, unlist bool) ([]int64, []TWItemFailure, error) {
+	var succeeded []int64
+	var failures []TWItemFailure
+	for start := 0; start < len(itemIdList); start += TWUnlistItemBatchSize {
+		end := start + TWUnlistItemBatchSize
+		if end > len(itemIdList) {
+			end = len(itemIdList)
+		}
+
+		req := TWUnlistItemReq{ItemList: make([]TWUnlistItem, 0, end-start)}
+		for _, itemId := range itemIdList[start:end] {
+			req.ItemList = append(req.ItemList, TWUnlistItem{ItemId: itemId, Unlist: unlist})
+		}
+		data, err := DoOpenAPIRequestWithResponse[TWUnlistItemData](c, ctx, OpenAPIRequest{
+			Endpoint: OpenAPIUnlistItem,
+			Auth:     auth,
+			Body:     req,
+		})
+		if err != nil {
+			return succeeded, failures, fmt.Errorf("上下架商品失败, batch=%d: %w", start/TWUnlistItemBatchSize, err)
+		}
+		for _, item := range data.SuccessList {
+			succeeded = append(succeeded, item.ItemId)
+		}
+		for _, item := range data.FailureList {
+			failures = append(failures, TWItemFailure{ItemId: item.ItemId, Reason: item.FailedReason})
+		}
+	}
+	return succeeded, failures, nil
+}
+
+// DeleteItemWithAreaTw 删除单个商品
+func (c *Client) DeleteItemWithAreaTw(ctx context.Context, auth OpenAPIAuth, itemId int64) error {
+	_, err := c.DoOpenAPIRequest(ctx, OpenAPIRequest{
+		Endpoint: OpenAPIDeleteItem,
+		Auth:     auth,
+		Body:     TWDeleteItemReq{ItemId: itemId},
+	})
+	return err
+}
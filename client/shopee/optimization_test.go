@@ -2,6 +2,7 @@ package shopee
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -28,6 +29,9 @@ func TestErrorHandling(t *testing.T) {
 	if !rateLimitErr.IsRetryable() {
 		t.Error("Rate limit errors should be retryable")
 	}
+	if !IsRateLimitError(fmt.Errorf("batch update failed: %w", rateLimitErr)) || IsRateLimitError(networkErr) {
+		t.Error("IsRateLimitError should match wrapped rate limit errors only")
+	}
 }
 
 func TestRequestManager(t *testing.T) {
@@ -83,17 +83,30 @@ type UpdateProductInfoReq struct {
 	ProductStatus ProductStatusInfo `json:"product_info,omitempty"`
 }
 
+// ModelDaysToShip 单个规格的出货天数
+type ModelDaysToShip struct {
+	ModelId    int64 `json:"model_id"`
+	DaysToShip int   `json:"days_to_ship"`
+}
+
 // PreOrderInfo 商品预售信息
 type PreOrderInfo struct {
 	PreOrder   bool `json:"pre_order"`
 	DaysToShip int  `json:"days_to_ship"`
 }
 
+// ModelPreOrderInfo 规格级别的预售信息
+type ModelPreOrderInfo struct {
+	ID           int64        `json:"id"`
+	PreOrderInfo PreOrderInfo `json:"pre_order_info"`
+}
+
 // ProductInfo 商品基础信息
 type ProductInfo struct {
-	EnableModelLevelDts bool         `json:"enable_model_level_dts"`
-	PreOrderInfo        PreOrderInfo `json:"pre_order_info"`
-	Unlisted            bool         `json:"unlisted"`
+	EnableModelLevelDts bool                `json:"enable_model_level_dts"`
+	PreOrderInfo        PreOrderInfo        `json:"pre_order_info"`
+	ModelList           []ModelPreOrderInfo `json:"model_list,omitempty"`
+	Unlisted            bool                `json:"unlisted"`
 }
 
 // UpdateProductInfoRequest 更新商品信息请求参数
@@ -103,6 +116,126 @@ type UpdateProductInfoRequest struct {
 	IsDraft     bool        `json:"is_draft"`
 }
 
+// ----------------------- 商品定义 ----------------------
+
+// ProductDefinition 商品的完整定义，获取商品详情与创建商品共用
+type ProductDefinition struct {
+	ID                  int64              `json:"id,omitempty"`
+	Name                string             `json:"name"`
+	Description         string             `json:"description"`
+	CategoryPath        []int64            `json:"category_path"`
+	BrandInfo           ProductBrandInfo   `json:"brand_info"`
+	Attributes          []ProductAttribute `json:"attributes"`
+	Images              []string           `json:"images"`
+	VideoList           []ProductVideo     `json:"video_list,omitempty"`
+	SizeChart           string             `json:"size_chart,omitempty"`
+	TierVariations      []TierVariation    `json:"std_tier_variation_list"`
+	ModelList           []ProductModelInfo `json:"model_list"`
+	Weight              ProductWeight      `json:"weight"`
+	Dimension           ProductDimension   `json:"dimension"`
+	LogisticsChannels   []LogisticsChannel `json:"logistics_channels"`
+	EnableModelLevelDts bool               `json:"enable_model_level_dts"`
+	PreOrderInfo        PreOrderInfo       `json:"pre_order_info"`
+	Condition           int                `json:"condition"`
+	ParentSku           string             `json:"parent_sku"`
+	Unlisted            bool               `json:"unlisted"`
+}
+
+// ProductBrandInfo 商品品牌
+type ProductBrandInfo struct {
+	BrandID           int64  `json:"brand_id"`
+	OriginalBrandName string `json:"original_brand_name"`
+}
+
+// ProductAttribute 商品属性，ValueID 为 0 时使用 RawValue
+type ProductAttribute struct {
+	AttributeID int64  `json:"attribute_id"`
+	ValueID     int64  `json:"attribute_value_id"`
+	RawValue    string `json:"raw_value,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+}
+
+// ProductVideo 商品视频，视频上传到店铺，不能在其他店铺使用
+type ProductVideo struct {
+	VideoID  string `json:"video_id"`
+	ThumbURL string `json:"thumb_url"`
+}
+
+// TierVariation 规格维度，例如颜色、尺码
+type TierVariation struct {
+	Name    string       `json:"name"`
+	Options []TierOption `json:"options"`
+}
+
+// TierOption 规格选项，Image 为选项图片 id
+type TierOption struct {
+	Option string `json:"option"`
+	Image  string `json:"image,omitempty"`
+}
+
+// ProductModelInfo 规格，价格为整数最小货币单位
+type ProductModelInfo struct {
+	ID               int64        `json:"id,omitempty"`
+	TierIndex        []int        `json:"tier_index"`
+	Sku              string       `json:"sku"`
+	InputNormalPrice int64        `json:"input_normal_price"`
+	SellerStock      int          `json:"seller_stock"`
+	PreOrderInfo     PreOrderInfo `json:"pre_order_info"`
+}
+
+// ProductWeight 商品重量
+type ProductWeight struct {
+	Unit  int    `json:"unit"`
+	Value string `json:"value"`
+}
+
+// ProductDimension 包裹尺寸(cm)
+type ProductDimension struct {
+	Width  int `json:"width"`
+	Length int `json:"length"`
+	Height int `json:"height"`
+}
+
+// LogisticsChannel 商品的物流渠道
+type LogisticsChannel struct {
+	ChannelID        int64  `json:"channel_id"`
+	Enabled          bool   `json:"enabled"`
+	SizeID           int64  `json:"size_id,omitempty"`
+	Price            string `json:"price,omitempty"`
+	CoverShippingFee bool   `json:"cover_shipping_fee"`
+}
+
+// createProductRequest 创建商品请求参数
+type createProductRequest struct {
+	ProductInfo ProductDefinition `json:"product_info"`
+	IsDraft     bool              `json:"is_draft"`
+}
+
+// ----------------------- 价格库存 ----------------------
+
+// getPriceStockInfoRequest 获取规格价格库存请求参数
+type getPriceStockInfoRequest struct {
+	ProductIdList []int64 `json:"product_id_list"`
+}
+
+// ModelPriceStockParam 单个规格的价格库存修改，价格为接口使用的整数最小货币单位
+type ModelPriceStockParam struct {
+	ModelId          int64  `json:"model_id"`
+	InputNormalPrice *int64 `json:"input_normal_price,omitempty"`
+	SellerStock      *int   `json:"seller_stock,omitempty"`
+}
+
+// UpdatePriceStockItem 单个商品的价格库存修改
+type UpdatePriceStockItem struct {
+	ProductId int64                  `json:"product_id"`
+	ModelList []ModelPriceStockParam `json:"model_list"`
+}
+
+// updatePriceStockRequest 批量修改价格库存请求参数
+type updatePriceStockRequest struct {
+	ProductList []UpdatePriceStockItem `json:"product_list"`
+}
+
 // ----------------------- 获取token信息 ----------------------
 // GetAccessTokenReq 获取 accessToken 请求参数
 type GetAccessTokenReq struct {
@@ -114,7 +247,7 @@ type GetAccessTokenReq struct {
 
 // ----------------------- 商品列表信息 ----------------------
 
-// ProductListRequest 商品列表请求
+// ProductListRequest 商品列表请求，范围条件为空表示不限制，时间为 unix 秒
 type ProductListRequest struct {
 	PageSize   int    `json:"page_size"`
 	PageNo     int    `json:"page_no"`
@@ -122,6 +255,74 @@ type ProductListRequest struct {
 	Keyword    string `json:"keyword,omitempty"`
 	SortBy     string `json:"sort_by,omitempty"`
 	SortType   int    `json:"sort_type,omitempty"`
+
+	ListType        string `json:"list_type,omitempty"`
+	CategoryId      int64  `json:"category_id,omitempty"`
+	StockMin        *int   `json:"stock_min,omitempty"`
+	StockMax        *int   `json:"stock_max,omitempty"`
+	SoldMin         *int   `json:"sold_min,omitempty"`
+	SoldMax         *int   `json:"sold_max,omitempty"`
+	CreateTimeStart int64  `json:"create_time_start,omitempty"`
+	CreateTimeEnd   int64  `json:"create_time_end,omitempty"`
+}
+
+// ToQuery 转换为搜索接口的查询参数，不包含分页与店铺参数
+func (r ProductListRequest) ToQuery() url.Values {
+	query := url.Values{}
+	if r.ListType != "" {
+		query.Set("list_type", r.ListType)
+	}
+	if r.Keyword != "" {
+		searchType := r.SearchType
+		if searchType == "" {
+			searchType = SearchTypeName
+		}
+		query.Set("search_type", searchType)
+		query.Set("keyword", r.Keyword)
+	}
+	if r.CategoryId > 0 {
+		query.Set("category_id", strconv.FormatInt(r.CategoryId, 10))
+	}
+	setInt := func(key string, value *int) {
+		if value != nil {
+			query.Set(key, strconv.Itoa(*value))
+		}
+	}
+	setInt("stock_min", r.StockMin)
+	setInt("stock_max", r.StockMax)
+	setInt("sold_min", r.SoldMin)
+	setInt("sold_max", r.SoldMax)
+	if r.CreateTimeStart > 0 {
+		query.Set("create_time_start", strconv.FormatInt(r.CreateTimeStart, 10))
+	}
+	if r.CreateTimeEnd > 0 {
+		query.Set("create_time_end", strconv.FormatInt(r.CreateTimeEnd, 10))
+	}
+	if r.SortBy != "" {
+		query.Set("sort_by", r.SortBy)
+		if r.SortType != 0 {
+			query.Set("sort_type", strconv.Itoa(r.SortType))
+		}
+	}
+	return query
+}
+
+// Match 校验接口返回的商品是否满足销量与创建时间条件，库存与类目由接口过滤
+func (r ProductListRequest) Match(product Product) bool {
+	sold := int(product.Statistics.SoldCount)
+	if r.SoldMin != nil && sold < *r.SoldMin {
+		return false
+	}
+	if r.SoldMax != nil && sold > *r.SoldMax {
+		return false
+	}
+	if r.CreateTimeStart > 0 && product.CreateTime < r.CreateTimeStart {
+		return false
+	}
+	if r.CreateTimeEnd > 0 && product.CreateTime > r.CreateTimeEnd {
+		return false
+	}
+	return true
 }
 
 type OngoingCampaigns struct {
@@ -0,0 +1,208 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// PriceStockBatchSize 价格库存接口单次请求最多包含的商品或规格数
+const PriceStockBatchSize = 50
+
+// Currency 区域货币，Decimals 为接口整数价格相对货币单位的小数位数
+type Currency struct {
+	Code     string
+	Decimals int
+}
+
+// regionCurrencies 各区域的货币，台湾、越南、印尼等无小数的货币按整数传递
+var regionCurrencies = map[string]Currency{
+	"SG": {"SGD", 2},
+	"MY": {"MYR", 2},
+	"TH": {"THB", 2},
+	"PH": {"PHP", 2},
+	"BR": {"BRL", 2},
+	"MX": {"MXN", 2},
+	"TW": {"TWD", 0},
+	"VN": {"VND", 0},
+	"ID": {"IDR", 0},
+	"CO": {"COP", 0},
+	"CL": {"CLP", 0},
+}
+
+// CurrencyOfRegion 获取区域的货币
+func CurrencyOfRegion(region string) (Currency, error) {
+	currency, ok := regionCurrencies[strings.ToUpper(region)]
+	if !ok {
+		return Currency{}, NewValidationError(fmt.Sprintf("不支持的区域: %s", region))
+	}
+	return currency, nil
+}
+
+// ToMinorUnits 将货币金额转换为接口使用的整数价格，超出货币精度的部分四舍五入
+func (cur Currency) ToMinorUnits(amount float64) (int64, error) {
+	if amount <= 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, NewValidationError(fmt.Sprintf("价格无效: %v", amount))
+	}
+	return int64(math.Round(amount * math.Pow10(cur.Decimals))), nil
+}
+
+// FromMinorUnits 将接口返回的整数价格转换为货币金额
+func (cur Currency) FromMinorUnits(value int64) float64 {
+	return float64(value) / math.Pow10(cur.Decimals)
+}
+
+// ModelPriceStock 规格的价格与库存，价格已按区域货币换算
+type ModelPriceStock struct {
+	ProductId      int64   `json:"product_id"`
+	ModelId        int64   `json:"model_id"`
+	Currency       string  `json:"currency"`
+	Price          float64 `json:"price"`           // 原价
+	PromotionPrice float64 `json:"promotion_price"` // 活动价，无活动时为 0
+	SellerStock    int     `json:"seller_stock"`
+	WmsStock       int     `json:"wms_stock"`
+}
+
+// ModelPriceStockChange 规格价格库存变更，Price 与 Stock 为空表示不修改
+type ModelPriceStockChange struct {
+	ProductId int64
+	ModelId   int64
+	Price     *float64
+	Stock     *int
+}
+
+// ModelPriceStockResult 规格修改结果
+type ModelPriceStockResult struct {
+	ProductId int64  `json:"product_id"`
+	ModelId   int64  `json:"model_id"`
+	Success   bool   `json:"success"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// GetModelPriceStock 获取商品下所有规格的价格与卖家库存
+func (c *Client) GetModelPriceStock(ctx context.Context, cookies, shopId, region string, productIds []int64) ([]ModelPriceStock, error) {
+	currency, err := CurrencyOfRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	param := CommomParam{shopId, region}
+
+	var result []ModelPriceStock
+	for start := 0; start < len(productIds); start += PriceStockBatchSize {
+		batch := productIds[start:minInt(start+PriceStockBatchSize, len(productIds))]
+		apiURL := APIPathGetPriceStockInfo + "?" + param.ToFormValues().Encode()
+		data, err := doCommonRequest[PriceStockInfoData](c, ctx, HTTPMethodPost, apiURL, &getPriceStockInfoRequest{ProductIdList: batch}, cookies)
+		if err != nil {
+			return result, fmt.Errorf("get price stock info failed: %w", err)
+		}
+		for _, info := range data.PriceStockInfo {
+			for _, sku := range info.SkuStockPriceList {
+				result = append(result, ModelPriceStock{
+					ProductId:      info.ItemID,
+					ModelId:        sku.ModelID,
+					Currency:       currency.Code,
+					Price:          currency.FromMinorUnits(sku.PriceInfo.InputNormalPrice),
+					PromotionPrice: currency.FromMinorUnits(sku.PriceInfo.InputPromotionPrice),
+					SellerStock:    sku.SellerStockInfo.NormalStock,
+					WmsStock:       sku.WmsStockInfo.NormalStock,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+// BatchUpdateModelPriceStock 批量修改规格价格与卖家库存，返回每个规格的结果
+// 参数无效的规格不会发送；单个请求失败时该请求内的规格全部记为失败，不影响其他请求
+func (c *Client) BatchUpdateModelPriceStock(ctx context.Context, cookies, shopId, region string,
+	changes []ModelPriceStockChange) ([]ModelPriceStockResult, error) {
+	currency, err := CurrencyOfRegion(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ModelPriceStockResult
+	var params []UpdatePriceStockItem // 每个元素只包含一个规格，发送前按商品合并
+	for _, change := range changes {
+		param := ModelPriceStockParam{ModelId: change.ModelId}
+		reason := ""
+		switch {
+		case change.Price == nil && change.Stock == nil:
+			reason = "价格与库存均未指定"
+		case change.Stock != nil && *change.Stock < 0:
+			reason = fmt.Sprintf("库存无效: %d", *change.Stock)
+		}
+		if reason == "" && change.Price != nil {
+			if price, err := currency.ToMinorUnits(*change.Price); err != nil {
+				reason = err.Error()
+			} else {
+				param.InputNormalPrice = &price
+			}
+		}
+		if reason != "" {
+			results = append(results, ModelPriceStockResult{ProductId: change.ProductId, ModelId: change.ModelId, Reason: reason})
+			continue
+		}
+		param.SellerStock = change.Stock
+		params = append(params, UpdatePriceStockItem{ProductId: change.ProductId, ModelList: []ModelPriceStockParam{param}})
+	}
+	sort.SliceStable(params, func(i, j int) bool { return params[i].ProductId < params[j].ProductId })
+
+	param := CommomParam{shopId, region}
+	for start := 0; start < len(params); start += PriceStockBatchSize {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		batch := params[start:minInt(start+PriceStockBatchSize, len(params))]
+		req := &updatePriceStockRequest{}
+		for _, item := range batch {
+			last := len(req.ProductList) - 1
+			if last >= 0 && req.ProductList[last].ProductId == item.ProductId {
+				req.ProductList[last].ModelList = append(req.ProductList[last].ModelList, item.ModelList...)
+				continue
+			}
+			req.ProductList = append(req.ProductList, UpdatePriceStockItem{
+				ProductId: item.ProductId,
+				ModelList: append([]ModelPriceStockParam(nil), item.ModelList...),
+			})
+		}
+
+		apiURL := APIPathUpdatePriceStock + "?" + param.ToFormValues().Encode()
+		data, err := doCommonRequest[UpdatePriceStockData](c, ctx, HTTPMethodPost, apiURL, req, cookies)
+		if err != nil {
+			for _, item := range batch {
+				results = append(results, ModelPriceStockResult{ProductId: item.ProductId, ModelId: item.ModelList[0].ModelId, Reason: err.Error()})
+			}
+			continue
+		}
+		results = append(results, data.results(batch)...)
+	}
+
+	logger.Info("规格价格库存批量修改完成", zap.String("shop_id", shopId), zap.Int("total", len(changes)))
+	return results, nil
+}
+
+// results 按请求顺序整理规格结果，接口未返回的规格视为成功
+func (d *UpdatePriceStockData) results(batch []UpdatePriceStockItem) []ModelPriceStockResult {
+	type key struct{ productId, modelId int64 }
+	returned := make(map[key]UpdatePriceStockResultItem, len(d.Result))
+	for _, item := range d.Result {
+		returned[key{item.ProductId, item.ModelId}] = item
+	}
+	results := make([]ModelPriceStockResult, 0, len(batch))
+	for _, item := range batch {
+		result := ModelPriceStockResult{ProductId: item.ProductId, ModelId: item.ModelList[0].ModelId, Success: true}
+		if returned, ok := returned[key{result.ProductId, result.ModelId}]; ok && returned.Code != ResponseCodeSuccess {
+			result.Success = false
+			result.Reason = fmt.Sprintf("code=%d, %s", returned.Code, returned.ErrorMessage)
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,96 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCurrencyConversion(t *testing.T) {
+	sgd, err := CurrencyOfRegion("sg")
+	if err != nil {
+		t.Fatalf("CurrencyOfRegion: %v", err)
+	}
+	if got, _ := sgd.ToMinorUnits(12.345); got != 1235 {
+		t.Errorf("SGD 12.345 = %d, want 1235", got)
+	}
+	if got := sgd.FromMinorUnits(1990); got != 19.9 {
+		t.Errorf("SGD 1990 = %v, want 19.9", got)
+	}
+
+	twd, _ := CurrencyOfRegion("TW")
+	if got, _ := twd.ToMinorUnits(299); got != 299 {
+		t.Errorf("TWD 299 = %d, want 299", got)
+	}
+	if _, err := twd.ToMinorUnits(-1); err == nil {
+		t.Error("negative price should fail")
+	}
+	if _, err := CurrencyOfRegion("XX"); err == nil {
+		t.Error("unknown region should fail")
+	}
+}
+
+func TestBatchUpdateModelPriceStock(t *testing.T) {
+	var sent updatePriceStockRequest
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPathGetPriceStockInfo:
+			json.NewEncoder(w).Encode(CommonResponse[PriceStockInfoData]{Data: PriceStockInfoData{
+				PriceStockInfo: []PriceStockInfo{{ItemID: 1, SkuStockPriceList: []SkuStockPriceEntry{{
+					ModelID:         11,
+					PriceInfo:       PriceInfo{InputNormalPrice: 1990},
+					SellerStockInfo: StockInfo{NormalStock: 5},
+				}}}},
+			}})
+		case APIPathUpdatePriceStock:
+			if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+				t.Errorf("decode request: %v", err)
+			}
+			json.NewEncoder(w).Encode(CommonResponse[UpdatePriceStockData]{Data: UpdatePriceStockData{
+				Result: []UpdatePriceStockResultItem{{ProductId: 2, ModelId: 21, Code: 1001, ErrorMessage: "price too low"}},
+			}})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	current, err := client.GetModelPriceStock(ctx, "cookie", "123", "SG", []int64{1})
+	if err != nil {
+		t.Fatalf("GetModelPriceStock: %v", err)
+	}
+	if len(current) != 1 || current[0].Price != 19.9 || current[0].SellerStock != 5 || current[0].Currency != "SGD" {
+		t.Fatalf("unexpected price stock %+v", current)
+	}
+
+	price, stock, badStock := 25.5, 10, -1
+	results, err := client.BatchUpdateModelPriceStock(ctx, "cookie", "123", "SG", []ModelPriceStockChange{
+		{ProductId: 2, ModelId: 21, Price: &price},
+		{ProductId: 1, ModelId: 11, Price: &price, Stock: &stock},
+		{ProductId: 1, ModelId: 12, Stock: &badStock},
+		{ProductId: 1, ModelId: 13},
+	})
+	if err != nil {
+		t.Fatalf("BatchUpdateModelPriceStock: %v", err)
+	}
+
+	if len(sent.ProductList) != 2 || sent.ProductList[0].ProductId != 1 || len(sent.ProductList[0].ModelList) != 1 {
+		t.Fatalf("unexpected request %+v", sent)
+	}
+	if got := sent.ProductList[0].ModelList[0]; *got.InputNormalPrice != 2550 || *got.SellerStock != 10 {
+		t.Errorf("unexpected model param %+v", got)
+	}
+	if sent.ProductList[1].ModelList[0].SellerStock != nil {
+		t.Error("stock should be omitted when not changed")
+	}
+
+	status := make(map[int64]bool)
+	for _, result := range results {
+		status[result.ModelId] = result.Success
+	}
+	if len(results) != 4 || !status[11] || status[12] || status[13] || status[21] {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
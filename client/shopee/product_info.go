@@ -0,0 +1,20 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// GetProductDefinition 获取商品的完整定义，包括属性、规格、图片、物流与出货天数
+func (c *Client) GetProductDefinition(ctx context.Context, cookies, shopId, region string, productId int64) (*ProductDefinition, error) {
+	param := CommomParam{shopId, region}
+	params := param.ToFormValues()
+	params.Set("product_id", strconv.FormatInt(productId, 10))
+	params.Set("is_draft", "false")
+	data, err := doCommonRequest[GetProductInfoData](c, ctx, HTTPMethodGet, APIPathGetProductInfo+"?"+params.Encode(), nil, cookies)
+	if err != nil {
+		return nil, fmt.Errorf("get product info failed, product_id=%d: %w", productId, err)
+	}
+	return &data.ProductInfo, nil
+}
@@ -0,0 +1,146 @@
+package shopee
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// PushCode Open Platform 推送类型
+type PushCode int
+
+const (
+	PushCodeShopAuthorization   PushCode = 1  // 店铺授权
+	PushCodeShopDeauthorization PushCode = 2  // 店铺取消授权
+	PushCodeOrderStatus         PushCode = 3  // 订单状态变更
+	PushCodeOrderTrackingNo     PushCode = 4  // 订单运单号更新
+	PushCodeShopeeUpdates       PushCode = 5  // 平台公告
+	PushCodeBannedItem          PushCode = 6  // 商品被禁
+	PushCodeAuthorizationExpiry PushCode = 12 // 授权即将过期
+)
+
+// PushMessage 推送原始消息
+type PushMessage struct {
+	Code       PushCode        `json:"code"`
+	ShopId     int64           `json:"shop_id"`
+	MerchantId int64           `json:"merchant_id"`
+	Timestamp  int64           `json:"timestamp"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// PushShopAuthorization 店铺授权/取消授权推送
+type PushShopAuthorization struct {
+	ShopId     int64  `json:"shop_id"`
+	MerchantId int64  `json:"merchant_id"`
+	PartnerId  int64  `json:"partner_id"`
+	Success    int    `json:"success"`
+	Extra      string `json:"extra"`
+}
+
+// PushOrderStatus 订单状态变更推送
+type PushOrderStatus struct {
+	OrderSn    string `json:"ordersn"`
+	Status     string `json:"status"`
+	UpdateTime int64  `json:"update_time"`
+}
+
+// PushOrderTrackingNo 订单运单号推送
+type PushOrderTrackingNo struct {
+	OrderSn       string `json:"ordersn"`
+	PackageNumber string `json:"package_number"`
+	TrackingNo    string `json:"tracking_no"`
+}
+
+// PushShopeeUpdates 平台公告推送
+type PushShopeeUpdates struct {
+	Actions []struct {
+		Content    string `json:"content"`
+		UpdateTime int64  `json:"update_time"`
+	} `json:"actions"`
+}
+
+// PushBannedItem 商品被禁推送
+type PushBannedItem struct {
+	ItemId     int64  `json:"item_id"`
+	ItemName   string `json:"item_name"`
+	ItemStatus string `json:"item_status"`
+	Reason     string `json:"reason"`
+	UpdateTime int64  `json:"update_time"`
+}
+
+// PushAuthorizationExpiry 授权即将过期推送
+type PushAuthorizationExpiry struct {
+	ExpireBefore       string  `json:"expire_before"`
+	ShopExpireSoon     []int64 `json:"shop_expire_soon"`
+	MerchantExpireSoon []int64 `json:"merchant_expire_soon"`
+}
+
+// PushEvent 解析后的推送事件，Data 为对应推送类型的结构体指针，未知类型保留原始 json
+type PushEvent struct {
+	Code       PushCode
+	ShopId     string
+	MerchantId string
+	Timestamp  int64
+	Data       interface{}
+}
+
+// VerifyPushSignature 校验推送请求头 Authorization: HMAC-SHA256(partner_key, callbackURL + "|" + body)
+func (c *Client) VerifyPushSignature(callbackURL string, body []byte, authorization string) bool {
+	if authorization == "" {
+		return false
+	}
+	_, partnerKey := c.openAPIPartner()
+	expected := SignOpenAPI(partnerKey, callbackURL, "|", string(body))
+	return hmac.Equal([]byte(expected), []byte(authorization))
+}
+
+// ParsePushEvent 解析推送消息体
+func ParsePushEvent(body []byte) (*PushEvent, error) {
+	var msg PushMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, NewParsingError("unmarshal push message failed", err)
+	}
+
+	event := &PushEvent{
+		Code:      msg.Code,
+		Timestamp: msg.Timestamp,
+		Data:      msg.Data,
+	}
+	if msg.ShopId != 0 {
+		event.ShopId = strconv.FormatInt(msg.ShopId, 10)
+	}
+	if msg.MerchantId != 0 {
+		event.MerchantId = strconv.FormatInt(msg.MerchantId, 10)
+	}
+
+	var data interface{}
+	switch msg.Code {
+	case PushCodeShopAuthorization, PushCodeShopDeauthorization:
+		data = &PushShopAuthorization{}
+	case PushCodeOrderStatus:
+		data = &PushOrderStatus{}
+	case PushCodeOrderTrackingNo:
+		data = &PushOrderTrackingNo{}
+	case PushCodeShopeeUpdates:
+		data = &PushShopeeUpdates{}
+	case PushCodeBannedItem:
+		data = &PushBannedItem{}
+	case PushCodeAuthorizationExpiry:
+		data = &PushAuthorizationExpiry{}
+	default:
+		return event, nil
+	}
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, data); err != nil {
+			return nil, NewParsingError(fmt.Sprintf("unmarshal push data failed, code=%d", msg.Code), err)
+		}
+	}
+	event.Data = data
+
+	// 授权类推送的 shop_id 可能只在 data 中
+	if auth, ok := data.(*PushShopAuthorization); ok && event.ShopId == "" && auth.ShopId != 0 {
+		event.ShopId = strconv.FormatInt(auth.ShopId, 10)
+	}
+	return event, nil
+}
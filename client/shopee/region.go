@@ -0,0 +1,37 @@
+package shopee
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// regionTimeZones 各区域的时区与标准时区偏移，系统缺少时区数据库时使用固定偏移
+var regionTimeZones = map[string]struct {
+	name   string
+	offset int // 相对 UTC 的小时数
+}{
+	"SG": {"Asia/Singapore", 8},
+	"MY": {"Asia/Kuala_Lumpur", 8},
+	"PH": {"Asia/Manila", 8},
+	"TW": {"Asia/Taipei", 8},
+	"TH": {"Asia/Bangkok", 7},
+	"VN": {"Asia/Ho_Chi_Minh", 7},
+	"ID": {"Asia/Jakarta", 7},
+	"BR": {"America/Sao_Paulo", -3},
+	"MX": {"America/Mexico_City", -6},
+	"CO": {"America/Bogota", -5},
+	"CL": {"America/Santiago", -4},
+}
+
+// LocationOfRegion 获取区域所在的时区
+func LocationOfRegion(region string) (*time.Location, error) {
+	zone, ok := regionTimeZones[strings.ToUpper(region)]
+	if !ok {
+		return nil, NewValidationError(fmt.Sprintf("不支持的区域: %s", region))
+	}
+	if loc, err := time.LoadLocation(zone.name); err == nil {
+		return loc, nil
+	}
+	return time.FixedZone(zone.name, zone.offset*3600), nil
+}
@@ -195,6 +195,24 @@ type DiscountItemData struct {
 	ErrorList        string             `json:"error_list"`
 }
 
+// PriceStockInfoData 规格价格库存 data
+type PriceStockInfoData struct {
+	PriceStockInfo []PriceStockInfo `json:"price_stock_info"`
+}
+
+// UpdatePriceStockResultItem 单个规格的修改结果
+type UpdatePriceStockResultItem struct {
+	ProductId    int64  `json:"product_id"`
+	ModelId      int64  `json:"model_id"`
+	Code         int    `json:"code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// UpdatePriceStockData 批量修改价格库存 data
+type UpdatePriceStockData struct {
+	Result []UpdatePriceStockResultItem `json:"result"`
+}
+
 // 删除折扣错误列表
 type DeleteDiscountData struct {
 	ErrorList []ItemError `json:"error_list"` // 错误详情，通常为 null
@@ -0,0 +1,104 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// DefaultSearchPageSize 搜索接口默认每页商品数
+const DefaultSearchPageSize = 48
+
+// SearchProducts 按条件搜索商品，逐页请求并将匹配的商品依次交给 fn，fn 返回 false 时停止翻页
+// req.PageNo 为起始页，默认第 1 页；req.ListType 为空时搜索全部商品
+func (c *Client) SearchProducts(ctx context.Context, cookies, shopId, region string,
+	req ProductListRequest, fn func(Product) bool) error {
+	if cookies == "" || shopId == "" || region == "" {
+		return NewValidationError("cookies、shopId、region 不能为空")
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = DefaultSearchPageSize
+	}
+	if req.PageNo <= 0 {
+		req.PageNo = 1
+	}
+	if req.ListType == "" {
+		req.ListType = ListTypeAll
+	}
+
+	param := CommomParam{shopId, region}
+	query := req.ToQuery()
+	query.Set("page_size", strconv.Itoa(req.PageSize))
+	for pageNo := req.PageNo; ; pageNo++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		params := param.ToFormValues()
+		for key, values := range query {
+			params[key] = values
+		}
+		params.Set("page_number", strconv.Itoa(pageNo))
+
+		data, err := c.searchProductPage(ctx, APIPathSearchProductList+"?"+params.Encode(), cookies)
+		if err != nil {
+			return fmt.Errorf("search product page %d failed: %w", pageNo, err)
+		}
+		for _, product := range data.Products {
+			if !req.Match(product) {
+				continue
+			}
+			if !fn(product) {
+				return nil
+			}
+		}
+		if len(data.Products) < req.PageSize || pageNo*req.PageSize >= data.PageInfo.Total {
+			logger.Debug("商品搜索完成", zap.String("shop_id", shopId), zap.Int("pages", pageNo-req.PageNo+1),
+				zap.Int("total", data.PageInfo.Total))
+			return nil
+		}
+	}
+}
+
+// SearchProductList 搜索商品并在拿到 limit 个匹配商品后停止，limit <= 0 表示不限制
+func (c *Client) SearchProductList(ctx context.Context, cookies, shopId, region string,
+	req ProductListRequest, limit int) ([]Product, error) {
+	var products []Product
+	err := c.SearchProducts(ctx, cookies, shopId, region, req, func(product Product) bool {
+		products = append(products, product)
+		return limit <= 0 || len(products) < limit
+	})
+	return products, err
+}
+
+func (c *Client) searchProductPage(ctx context.Context, apiURL, cookies string) (*ProductListData, error) {
+	resp, err := c.doRequestWithContext(ctx, HTTPMethodGet, apiURL, nil, cookies)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+	if resp.StatusCode == RateLimitCode {
+		return nil, fmt.Errorf(RateLimitError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d, message: %s", resp.StatusCode, string(body))
+	}
+	var pageResp ProductListResponse
+	if err := json.Unmarshal(body, &pageResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+	if pageResp.Code != ResponseCodeSuccess {
+		return nil, fmt.Errorf("code=%d, message: %s", pageResp.Code, pageResp.UserMessage)
+	}
+	return &pageResp.Data, nil
+}
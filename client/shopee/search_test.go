@@ -0,0 +1,69 @@
+package shopee
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestSearchProductList(t *testing.T) {
+	var pages []int
+	client, server := newOpenAPITestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != APIPathSearchProductList {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("keyword") != "shirt" || query.Get("search_type") != SearchTypeName ||
+			query.Get("category_id") != "100" || query.Get("stock_max") != "10" || query.Get("sort_by") != SortBySold {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		page, _ := strconv.Atoi(query.Get("page_number"))
+		pages = append(pages, page)
+
+		// 每页 2 个商品，共 10 个，偶数 id 销量为 0
+		var products []Product
+		for i := 0; i < 2; i++ {
+			id := (page-1)*2 + i + 1
+			products = append(products, Product{ID: id, Statistics: ProductStatistics{SoldCount: int64(id % 2)}})
+		}
+		json.NewEncoder(w).Encode(ProductListResponse{Data: ProductListData{
+			Products: products,
+			PageInfo: PageInfo{Total: 10},
+		}})
+	})
+	defer server.Close()
+
+	soldMin, stockMax := 1, 10
+	products, err := client.SearchProductList(context.Background(), "cookie", "123", "SG", ProductListRequest{
+		PageSize:   2,
+		Keyword:    "shirt",
+		CategoryId: 100,
+		StockMax:   &stockMax,
+		SoldMin:    &soldMin,
+		SortBy:     SortBySold,
+		SortType:   SortTypeDesc,
+	}, 2)
+	if err != nil {
+		t.Fatalf("SearchProductList: %v", err)
+	}
+	if len(products) != 2 || products[0].ID != 1 || products[1].ID != 3 {
+		t.Fatalf("unexpected products %+v", products)
+	}
+	if len(pages) != 2 {
+		t.Errorf("search should stop after the limit is reached, requested pages %v", pages)
+	}
+
+	pages = nil
+	products, err = client.SearchProductList(context.Background(), "cookie", "123", "SG", ProductListRequest{
+		PageSize: 2, Keyword: "shirt", CategoryId: 100, StockMax: &stockMax, SortBy: SortBySold,
+	}, 0)
+	if err != nil {
+		t.Fatalf("SearchProductList: %v", err)
+	}
+	if len(products) != 10 || len(pages) != 5 {
+		t.Errorf("expected all 10 products from 5 pages, got %d products from %v", len(products), pages)
+	}
+}
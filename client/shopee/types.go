@@ -1,7 +1,14 @@
 package shopee
 
-// IsActiveProduct 判断是否是活跃商品
+// IsActiveProduct 判断点赞、销量、浏览是否全为 0
+//
+// Deprecated: 名称与含义相反，请使用 IsZeroActivity
 func (stat ProductStatistics) IsActiveProduct() bool {
+	return stat.IsZeroActivity()
+}
+
+// IsZeroActivity 点赞、销量、浏览全为 0
+func (stat ProductStatistics) IsZeroActivity() bool {
 	return stat.LikedCount == 0 && stat.SoldCount == 0 && stat.ViewCount == 0
 }
 
@@ -38,6 +45,8 @@ type ProductDetailListData struct {
 
 type ProductDetail struct {
 	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	CreateTime    int64   `json:"create_time"`
 	DaysToShip    int     `json:"days_to_ship"`
 	EstimatedDays int     `json:"estimated_days"`
 	PreOrder      bool    `json:"pre_order"`
@@ -73,6 +82,7 @@ type UpdateProductRequest struct {
 type TWGetAccessTokenResp struct {
 	ErrorCode    string `json:"error"`
 	Message      string `json:"message"`
+	RequestId    string `json:"request_id"`
 	AccessToken  string `json:"access_token"`
 	ExpireIn     int64  `json:"expire_in"`
 	RefreshToken string `json:"refresh_token"`
@@ -81,6 +91,7 @@ type TWGetAccessTokenResp struct {
 type TWProductItem struct {
 	ItemId     int64  `json:"item_id"`
 	ItemStatus string `json:"item_status"`
+	UpdateTime int64  `json:"update_time"`
 }
 
 type TWProductListData struct {
@@ -159,10 +170,25 @@ type ProductPreOrderInfoWithAreaTwItem struct {
 	IsPreOrder bool `json:"is_pre_order"`
 }
 
+// ProductPriceInfoWithAreaTw 商品价格信息，存在规格时价格在规格上
+type ProductPriceInfoWithAreaTw struct {
+	Currency      string  `json:"currency"`
+	OriginalPrice float64 `json:"original_price"`
+	CurrentPrice  float64 `json:"current_price"`
+}
+
 // ProductBaseInfoWithAreaTw 商品基础信息返回参数
 type ProductBaseInfoWithAreaTw struct {
-	ItemId   int64                             `json:"item_id"`
-	PreOrder ProductPreOrderInfoWithAreaTwItem `json:"pre_order"`
+	ItemId     int64                             `json:"item_id"`
+	CategoryId int64                             `json:"category_id"`
+	ItemName   string                            `json:"item_name"`
+	ItemSku    string                            `json:"item_sku"`
+	ItemStatus string                            `json:"item_status"`
+	CreateTime int64                             `json:"create_time"`
+	UpdateTime int64                             `json:"update_time"`
+	HasModel   bool                              `json:"has_model"`
+	PriceInfo  []ProductPriceInfoWithAreaTw      `json:"price_info"`
+	PreOrder   ProductPreOrderInfoWithAreaTwItem `json:"pre_order"`
 }
 
 // ProductBaseInfoWithAreaTw tw基本商品信息--平铺展示
@@ -174,8 +200,206 @@ type ProductBaseInfoWithAreaTwComplate struct {
 
 // BatchUpdateProductInfoItem 批量更新商品信息
 type BatchUpdateProductInfoItem struct {
-	ID         int64 `json:"id"`
-	PreOrder   bool  `json:"pre_order,omitempty"`
-	DaysToShip int   `json:"days_to_ship,omitempty"`
-	Unlisted   bool  `json:"unlisted"`
+	ID                  int64               `json:"id"`
+	PreOrder            bool                `json:"pre_order,omitempty"`
+	DaysToShip          int                 `json:"days_to_ship,omitempty"`
+	EnableModelLevelDts bool                `json:"enable_model_level_dts,omitempty"`
+	ModelList           []ModelPreOrderInfo `json:"model_list,omitempty"`
+	Unlisted            bool                `json:"unlisted"`
+}
+
+// TWOrderListData 台湾订单列表响应
+type TWOrderListData struct {
+	More       bool          `json:"more"`
+	NextCursor string        `json:"next_cursor"`
+	OrderList  []TWOrderItem `json:"order_list"`
+}
+
+// TWOrderItem 订单列表项
+type TWOrderItem struct {
+	OrderSn     string `json:"order_sn"`
+	OrderStatus string `json:"order_status"`
+}
+
+// TWOrderDetailListData 台湾订单详情响应
+type TWOrderDetailListData struct {
+	OrderList []TWOrderDetail `json:"order_list"`
+}
+
+// TWOrderDetail 订单详情
+type TWOrderDetail struct {
+	OrderSn              string              `json:"order_sn"`
+	Region               string              `json:"region"`
+	Currency             string              `json:"currency"`
+	Cod                  bool                `json:"cod"`
+	TotalAmount          float64             `json:"total_amount"`
+	OrderStatus          string              `json:"order_status"`
+	ShippingCarrier      string              `json:"shipping_carrier"`
+	PaymentMethod        string              `json:"payment_method"`
+	EstimatedShippingFee float64             `json:"estimated_shipping_fee"`
+	MessageToSeller      string              `json:"message_to_seller"`
+	DaysToShip           int                 `json:"days_to_ship"`
+	ShipByDate           int64               `json:"ship_by_date"`
+	BuyerUserId          int64               `json:"buyer_user_id"`
+	BuyerUsername        string              `json:"buyer_username"`
+	CreateTime           int64               `json:"create_time"`
+	UpdateTime           int64               `json:"update_time"`
+	PayTime              int64               `json:"pay_time"`
+	RecipientAddress     TWOrderAddress      `json:"recipient_address"`
+	ItemList             []TWOrderDetailItem `json:"item_list"`
+	PackageList          []TWOrderPackage    `json:"package_list"`
+}
+
+// TWOrderAddress 收件地址
+type TWOrderAddress struct {
+	Name        string `json:"name"`
+	Phone       string `json:"phone"`
+	Town        string `json:"town"`
+	District    string `json:"district"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Region      string `json:"region"`
+	Zipcode     string `json:"zipcode"`
+	FullAddress string `json:"full_address"`
+}
+
+// TWOrderDetailItem 订单商品
+type TWOrderDetailItem struct {
+	ItemId                 int64   `json:"item_id"`
+	ItemName               string  `json:"item_name"`
+	ItemSku                string  `json:"item_sku"`
+	ModelId                int64   `json:"model_id"`
+	ModelName              string  `json:"model_name"`
+	ModelSku               string  `json:"model_sku"`
+	ModelQuantityPurchased int     `json:"model_quantity_purchased"`
+	ModelOriginalPrice     float64 `json:"model_original_price"`
+	ModelDiscountedPrice   float64 `json:"model_discounted_price"`
+}
+
+// TWOrderPackage 订单包裹
+type TWOrderPackage struct {
+	PackageNumber   string `json:"package_number"`
+	LogisticsStatus string `json:"logistics_status"`
+	ShippingCarrier string `json:"shipping_carrier"`
+}
+
+// TWShippingParameter 订单发货参数
+type TWShippingParameter struct {
+	InfoNeeded struct {
+		Dropoff       []string `json:"dropoff"`
+		Pickup        []string `json:"pickup"`
+		NonIntegrated []string `json:"non_integrated"`
+	} `json:"info_needed"`
+	Dropoff struct {
+		BranchList []TWShippingBranch `json:"branch_list"`
+	} `json:"dropoff"`
+	Pickup struct {
+		AddressList []TWShippingPickupAddress `json:"address_list"`
+	} `json:"pickup"`
+}
+
+// TWShippingBranch 可选的寄件门店
+type TWShippingBranch struct {
+	BranchId int64  `json:"branch_id"`
+	Region   string `json:"region"`
+	State    string `json:"state"`
+	City     string `json:"city"`
+	Address  string `json:"address"`
+	Zipcode  string `json:"zipcode"`
+	District string `json:"district"`
+	Town     string `json:"town"`
+}
+
+// TWShippingPickupAddress 可选的揽收地址
+type TWShippingPickupAddress struct {
+	AddressId    int64    `json:"address_id"`
+	Region       string   `json:"region"`
+	State        string   `json:"state"`
+	City         string   `json:"city"`
+	District     string   `json:"district"`
+	Town         string   `json:"town"`
+	Address      string   `json:"address"`
+	Zipcode      string   `json:"zipcode"`
+	AddressFlag  []string `json:"address_flag"`
+	TimeSlotList []struct {
+		Date         int64  `json:"date"`
+		TimeText     string `json:"time_text"`
+		PickupTimeId string `json:"pickup_time_id"`
+	} `json:"time_slot_list"`
+}
+
+// TWModelPrice 规格价格
+type TWModelPrice struct {
+	ModelId       int64   `json:"model_id,omitempty"`
+	OriginalPrice float64 `json:"original_price"`
+}
+
+// TWUpdatePriceReq 更新价格请求
+type TWUpdatePriceReq struct {
+	ItemId    int64          `json:"item_id"`
+	PriceList []TWModelPrice `json:"price_list"`
+}
+
+// TWSellerStock 卖家库存
+type TWSellerStock struct {
+	LocationId string `json:"location_id,omitempty"`
+	Stock      int    `json:"stock"`
+}
+
+// TWModelStock 规格库存
+type TWModelStock struct {
+	ModelId     int64           `json:"model_id,omitempty"`
+	SellerStock []TWSellerStock `json:"seller_stock"`
+}
+
+// TWUpdateStockReq 更新库存请求
+type TWUpdateStockReq struct {
+	ItemId    int64          `json:"item_id"`
+	StockList []TWModelStock `json:"stock_list"`
+}
+
+// TWModelUpdateData update_price/update_stock 响应
+type TWModelUpdateData struct {
+	SuccessList []struct {
+		ModelId int64 `json:"model_id"`
+	} `json:"success_list"`
+	FailureList []struct {
+		ModelId      int64  `json:"model_id"`
+		FailedReason string `json:"failed_reason"`
+	} `json:"failure_list"`
+}
+
+// TWUnlistItem 上下架商品
+type TWUnlistItem struct {
+	ItemId int64 `json:"item_id"`
+	Unlist bool  `json:"unlist"`
+}
+
+// TWUnlistItemReq 上下架商品请求
+type TWUnlistItemReq struct {
+	ItemList []TWUnlistItem `json:"item_list"`
+}
+
+// TWUnlistItemData 上下架商品响应
+type TWUnlistItemData struct {
+	SuccessList []TWUnlistItem `json:"success_list"`
+	FailureList []struct {
+		ItemId       int64  `json:"item_id"`
+		FailedReason string `json:"failed_reason"`
+	} `json:"failure_list"`
+}
+
+// TWDeleteItemReq 删除商品请求
+type TWDeleteItemReq struct {
+	ItemId int64 `json:"item_id"`
+}
+
+// GetProductInfoData 获取商品详情响应
+type GetProductInfoData struct {
+	ProductInfo ProductDefinition `json:"product_info"`
+}
+
+// CreateProductData 创建商品响应
+type CreateProductData struct {
+	ProductID int64 `json:"product_id"`
 }
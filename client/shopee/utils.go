@@ -26,30 +26,40 @@ func MD5Hash(text string) string {
 	return fmt.Sprintf("%x", hash)
 }
 
-// Shop API sign
-func GetShopSign(partnerId, path, timestamp, accessToken, shopId string) string {
-	tmpBaseString := fmt.Sprintf("%s%s%s%s%s", partnerId,
-		path, timestamp, accessToken, shopId)
-	mac := hmac.New(sha256.New, []byte(constant.PartnerKey))
+// SignOpenAPI 使用 partnerKey 对 base_string 各部分拼接后做 HMAC-SHA256 签名
+func SignOpenAPI(partnerKey string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(partnerKey))
 	// 写入待加密的 base_string
-	mac.Write([]byte(tmpBaseString))
+	mac.Write([]byte(strings.Join(parts, "")))
 
 	// 获取签名结果并转换为十六进制字符串
-	signature := hex.EncodeToString(mac.Sum(nil))
-	return signature
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Shop API sign
+//
+// Deprecated: 固定使用 constant.PartnerKey，请通过 Client 发起请求以使用客户端配置的 partner
+func GetShopSign(partnerId, path, timestamp, accessToken, shopId string) string {
+	return SignOpenAPI(constant.PartnerKey, partnerId, path, timestamp, accessToken, shopId)
 }
 
 // Public API sign
+//
+// Deprecated: 固定使用 constant.PartnerKey，请通过 Client 发起请求以使用客户端配置的 partner
 func GetPublicSign(partnerId, path, timestamp string) string {
-	tmpBaseString := fmt.Sprintf("%s%s%s", partnerId,
-		path, timestamp)
-	mac := hmac.New(sha256.New, []byte(constant.PartnerKey))
-	// 写入待加密的 base_string
-	mac.Write([]byte(tmpBaseString))
+	return SignOpenAPI(constant.PartnerKey, partnerId, path, timestamp)
+}
 
-	// 获取签名结果并转换为十六进制字符串
-	signature := hex.EncodeToString(mac.Sum(nil))
-	return signature
+// shopSign 店铺接口签名，使用客户端配置的 partner
+func (c *Client) shopSign(path, timestamp, accessToken, shopId string) string {
+	partnerId, partnerKey := c.openAPIPartner()
+	return SignOpenAPI(partnerKey, partnerId, path, timestamp, accessToken, shopId)
+}
+
+// publicSign 公共接口签名，使用客户端配置的 partner
+func (c *Client) publicSign(path, timestamp string) string {
+	partnerId, partnerKey := c.openAPIPartner()
+	return SignOpenAPI(partnerKey, partnerId, path, timestamp)
 }
 
 // formatPhone 格式化手机号
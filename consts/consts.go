@@ -7,4 +7,19 @@ const (
 	DiscountTable      = "discount"
 	ShopeeAccountTable = "shopee_accounts"
 	ParentAccountTable = "parent_accounts"
+	ProductSyncTable   = "product_sync_states"
+
+	ProductSnapshotTable     = "product_snapshots"
+	ProductSnapshotItemTable = "product_snapshot_items"
+
+	ProductTable      = "products"
+	ProductModelTable = "product_models"
+
+	ProductStatSnapshotTable = "product_stat_snapshots"
+
+	ProtectedProductTable = "protected_products"
+	ProductDeletionTable  = "product_deletions"
+
+	ListingWindowTable     = "listing_windows"
+	ListingWindowItemTable = "listing_window_items"
 )
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/consts"
+)
+
+// ListingWindow 动作，窗口开始时执行，结束时反向执行
+const (
+	ListingWindowActionUnlist = "unlist" // 窗口期间下架，如节假日闭店
+	ListingWindowActionList   = "list"   // 窗口期间上架，如限时活动
+)
+
+// ListingWindow 状态
+const (
+	ListingWindowStatusScheduled = "scheduled"
+	ListingWindowStatusActive    = "active"    // 已执行，等待结束时恢复
+	ListingWindowStatusCompleted = "completed" // 已恢复
+	ListingWindowStatusExpired   = "expired"   // 开始前未执行且已过结束时间
+	ListingWindowStatusCanceled  = "canceled"
+)
+
+// ListingWindowItem 状态
+const (
+	ListingWindowItemStatusPending  = "pending"
+	ListingWindowItemStatusApplied  = "applied" // 本窗口修改过，结束时需要恢复
+	ListingWindowItemStatusSkipped  = "skipped" // 开始时已处于目标状态或不存在，不做修改
+	ListingWindowItemStatusFailed   = "failed"
+	ListingWindowItemStatusReverted = "reverted"
+)
+
+// ListingWindow 店铺商品的定时上下架窗口，StartAt/EndAt 为 UTC 时间
+type ListingWindow struct {
+	ID          int64      `json:"id" gorm:"column:id;primaryKey"`
+	ShopID      string     `json:"shop_id" gorm:"column:shop_id;size:64;not null;index"`
+	Region      string     `json:"region" gorm:"column:region;size:16;not null"`
+	Name        string     `json:"name" gorm:"column:name;size:128"`
+	Action      string     `json:"action" gorm:"column:action;size:32;not null"`
+	AllProducts bool       `json:"all_products" gorm:"column:all_products;not null;default:false"` // 作用于开始时店铺的全部商品
+	StartAt     time.Time  `json:"start_at" gorm:"column:start_at;not null;index:idx_status_time"`
+	EndAt       time.Time  `json:"end_at" gorm:"column:end_at;not null"`
+	Status      string     `json:"status" gorm:"column:status;size:32;not null;index:idx_status_time"`
+	AppliedAt   *time.Time `json:"applied_at" gorm:"column:applied_at"`
+	RevertedAt  *time.Time `json:"reverted_at" gorm:"column:reverted_at"`
+	Error       string     `json:"error" gorm:"column:error;size:512"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (w *ListingWindow) TableName() string {
+	return consts.ListingWindowTable
+}
+
+// ListingWindowItem 窗口中的单个商品及其修改记录
+type ListingWindowItem struct {
+	ID        int64     `json:"id" gorm:"column:id;primaryKey"`
+	WindowID  int64     `json:"window_id" gorm:"column:window_id;not null;uniqueIndex:uk_window_product"`
+	ProductID int64     `json:"product_id" gorm:"column:product_id;not null;uniqueIndex:uk_window_product"`
+	Status    string    `json:"status" gorm:"column:status;size:32;not null"`
+	Reason    string    `json:"reason" gorm:"column:reason;size:512"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (i *ListingWindowItem) TableName() string {
+	return consts.ListingWindowItemTable
+}
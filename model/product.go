@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/consts"
+)
+
+// Product 状态
+const (
+	ProductStatusLive     = "live"
+	ProductStatusUnlisted = "unlisted"
+	ProductStatusDeleted  = "deleted" // 最近一次同步中未出现
+)
+
+// Product 店铺商品目录，由同步任务按店铺全量更新
+type Product struct {
+	ID           int64      `json:"id" gorm:"column:id;primaryKey"`
+	ShopID       string     `json:"shop_id" gorm:"column:shop_id;size:64;not null;uniqueIndex:uk_shop_product"`
+	Region       string     `json:"region" gorm:"column:region;size:16"`
+	ProductID    int64      `json:"product_id" gorm:"column:product_id;not null;uniqueIndex:uk_shop_product"`
+	Name         string     `json:"name" gorm:"column:name;size:512"`
+	Status       string     `json:"status" gorm:"column:status;size:32;not null"`
+	DaysToShip   int        `json:"days_to_ship" gorm:"column:days_to_ship;not null;default:0"`
+	PreOrder     bool       `json:"pre_order" gorm:"column:pre_order;not null;default:false"`
+	LikedCount   int64      `json:"liked_count" gorm:"column:liked_count;not null;default:0"`
+	SoldCount    int64      `json:"sold_count" gorm:"column:sold_count;not null;default:0"`
+	ViewCount    int64      `json:"view_count" gorm:"column:view_count;not null;default:0"`
+	ModelCount   int        `json:"model_count" gorm:"column:model_count;not null;default:0"`
+	CreateTime   int64      `json:"create_time" gorm:"column:create_time;not null;default:0"` // 商品在 Shopee 的创建时间
+	LastSyncedAt time.Time  `json:"last_synced_at" gorm:"column:last_synced_at"`
+	RemovedAt    *time.Time `json:"removed_at" gorm:"column:removed_at"` // 标记为 deleted 的时间
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (p *Product) TableName() string {
+	return consts.ProductTable
+}
+
+// ProductModel 商品规格
+type ProductModel struct {
+	ID        int64     `json:"id" gorm:"column:id;primaryKey"`
+	ShopID    string    `json:"shop_id" gorm:"column:shop_id;size:64;not null"`
+	ProductID int64     `json:"product_id" gorm:"column:product_id;not null;uniqueIndex:uk_product_model"`
+	ModelID   int64     `json:"model_id" gorm:"column:model_id;not null;uniqueIndex:uk_product_model"`
+	Name      string    `json:"name" gorm:"column:name;size:255"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (m *ProductModel) TableName() string {
+	return consts.ProductModelTable
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/consts"
+)
+
+// ProductDeletion 状态
+const (
+	ProductDeletionStatusPending = "pending" // 已归档，尚未得到删除结果
+	ProductDeletionStatusDeleted = "deleted"
+	ProductDeletionStatusFailed  = "failed"
+)
+
+// ProtectedProduct 受保护的商品，删除时会被跳过
+type ProtectedProduct struct {
+	ID        int64     `json:"id" gorm:"column:id;primaryKey"`
+	ShopID    string    `json:"shop_id" gorm:"column:shop_id;size:64;not null;uniqueIndex:uk_shop_product"`
+	ProductID int64     `json:"product_id" gorm:"column:product_id;not null;uniqueIndex:uk_shop_product"`
+	Reason    string    `json:"reason" gorm:"column:reason;size:255"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (p *ProtectedProduct) TableName() string {
+	return consts.ProtectedProductTable
+}
+
+// ProductDeletion 删除前归档的商品记录，Payload 为删除前完整商品信息的 JSON
+type ProductDeletion struct {
+	ID        int64     `json:"id" gorm:"column:id;primaryKey"`
+	RequestID string    `json:"request_id" gorm:"column:request_id;size:64;not null;index"` // 同一次删除请求共用
+	ShopID    string    `json:"shop_id" gorm:"column:shop_id;size:64;not null;index:idx_shop_created"`
+	Region    string    `json:"region" gorm:"column:region;size:16"`
+	ProductID int64     `json:"product_id" gorm:"column:product_id;not null"`
+	Name      string    `json:"name" gorm:"column:name;size:512"`
+	Payload   string    `json:"payload" gorm:"column:payload;type:text"`
+	Status    string    `json:"status" gorm:"column:status;size:32;not null"`
+	Reason    string    `json:"reason" gorm:"column:reason;size:512"` // 删除失败原因
+	Operator  string    `json:"operator" gorm:"column:operator;size:64"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime;index:idx_shop_created"`
+}
+
+func (d *ProductDeletion) TableName() string {
+	return consts.ProductDeletionTable
+}
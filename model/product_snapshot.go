@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/consts"
+)
+
+// ProductSnapshot 状态
+const (
+	ProductSnapshotStatusCreated    = "created"
+	ProductSnapshotStatusRolledBack = "rolled_back"
+)
+
+// ProductSnapshot 批量修改前的商品状态快照，Version 按店铺递增
+type ProductSnapshot struct {
+	ID           int64      `json:"id" gorm:"column:id;primaryKey"`
+	ShopID       string     `json:"shop_id" gorm:"column:shop_id;size:64;not null;uniqueIndex:uk_shop_version"`
+	Region       string     `json:"region" gorm:"column:region;size:16"`
+	Version      int        `json:"version" gorm:"column:version;not null;uniqueIndex:uk_shop_version"`
+	Operation    string     `json:"operation" gorm:"column:operation;size:64"` // 触发快照的批量操作
+	ItemCount    int        `json:"item_count" gorm:"column:item_count;not null;default:0"`
+	Status       string     `json:"status" gorm:"column:status;size:32"`
+	RolledBackAt *time.Time `json:"rolled_back_at" gorm:"column:rolled_back_at"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (s *ProductSnapshot) TableName() string {
+	return consts.ProductSnapshotTable
+}
+
+// ProductSnapshotItem 快照中单个商品修改前的状态
+type ProductSnapshotItem struct {
+	ID         int64 `json:"id" gorm:"column:id;primaryKey"`
+	SnapshotID int64 `json:"snapshot_id" gorm:"column:snapshot_id;not null;index"`
+	ProductID  int64 `json:"product_id" gorm:"column:product_id;not null"`
+	Unlisted   bool  `json:"unlisted" gorm:"column:unlisted;not null;default:false"`
+	DaysToShip int   `json:"days_to_ship" gorm:"column:days_to_ship;not null;default:0"`
+	PreOrder   bool  `json:"pre_order" gorm:"column:pre_order;not null;default:false"`
+}
+
+func (i *ProductSnapshotItem) TableName() string {
+	return consts.ProductSnapshotItemTable
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/consts"
+)
+
+// ProductStatSnapshotDateLayout SnapshotDate 的格式
+const ProductStatSnapshotDateLayout = "2006-01-02"
+
+// ProductStatSnapshot 商品每日累计统计，同一天多次采集以最后一次为准
+type ProductStatSnapshot struct {
+	ID           int64     `json:"id" gorm:"column:id;primaryKey"`
+	ShopID       string    `json:"shop_id" gorm:"column:shop_id;size:64;not null;uniqueIndex:uk_shop_product_date"`
+	ProductID    int64     `json:"product_id" gorm:"column:product_id;not null;uniqueIndex:uk_shop_product_date"`
+	SnapshotDate string    `json:"snapshot_date" gorm:"column:snapshot_date;size:10;not null;uniqueIndex:uk_shop_product_date"`
+	LikedCount   int64     `json:"liked_count" gorm:"column:liked_count;not null;default:0"`
+	SoldCount    int64     `json:"sold_count" gorm:"column:sold_count;not null;default:0"`
+	ViewCount    int64     `json:"view_count" gorm:"column:view_count;not null;default:0"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (s *ProductStatSnapshot) TableName() string {
+	return consts.ProductStatSnapshotTable
+}
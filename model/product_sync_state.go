@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/consts"
+)
+
+// ProductSyncState 店铺商品增量同步进度
+type ProductSyncState struct {
+	ID             int64     `json:"id" gorm:"column:id;primaryKey"`
+	ShopID         string    `json:"shop_id" gorm:"column:shop_id;size:64;uniqueIndex;not null"`
+	LastUpdateTime int64     `json:"last_update_time" gorm:"column:last_update_time;not null;default:0"` // 已同步到的商品 update_time
+	LastSyncAt     time.Time `json:"last_sync_at" gorm:"column:last_sync_at"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (s *ProductSyncState) TableName() string {
+	return consts.ProductSyncTable
+}
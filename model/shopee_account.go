@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/donghui12/shopee_tool_base/consts"
@@ -21,6 +22,18 @@ func (a *ParentAccount) TableName() string {
 	return consts.ParentAccountTable
 }
 
+// ExpiredAtLayout ShopeeAccount.ExpiredAt 的存储格式，带时区偏移
+const ExpiredAtLayout = time.RFC3339
+
+// ExpiredAtLocalLayout 旧数据使用的格式(进程本地时间)，仅用于解析
+const ExpiredAtLocalLayout = "2006-01-02 15:04:05"
+
+// ShopeeAccount 状态
+const (
+	ShopeeAccountStatusActive   = "active"
+	ShopeeAccountStatusInactive = "inactive"
+)
+
 // Shopee 子账号表
 type ShopeeAccount struct {
 	ID              int64     `gorm:"primaryKey;autoIncrement"`
@@ -37,3 +50,20 @@ type ShopeeAccount struct {
 func (a *ShopeeAccount) TableName() string {
 	return consts.ShopeeAccountTable
 }
+
+// ExpireTime 解析 token 过期时间，兼容不带时区的旧数据
+func (a *ShopeeAccount) ExpireTime() (time.Time, error) {
+	if expireTime, err := time.Parse(ExpiredAtLayout, a.ExpiredAt); err == nil {
+		return expireTime, nil
+	}
+	expireTime, err := time.ParseInLocation(ExpiredAtLocalLayout, a.ExpiredAt, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("解析过期时间失败: %s, %w", a.ExpiredAt, err)
+	}
+	return expireTime, nil
+}
+
+// IsInactive 是否已失效(取消授权等)
+func (a *ShopeeAccount) IsInactive() bool {
+	return a.Status == ShopeeAccountStatusInactive
+}
@@ -0,0 +1,319 @@
+// Package xlsx 读写单工作表的 xlsx 文件，支持文本、数字与日期单元格，满足批量编辑模板与导出的需要
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	workbookPath      = "xl/workbook.xml"
+	sharedStringsPath = "xl/sharedStrings.xml"
+	stylesPath        = "xl/styles.xml"
+	firstSheetPath    = "xl/worksheets/sheet1.xml"
+)
+
+// DateFormat 日期单元格的显示格式
+const DateFormat = "yyyy-mm-dd hh:mm:ss"
+
+// dateStyleIndex styles.xml 中日期格式的 cellXfs 下标
+const dateStyleIndex = 1
+
+// Write 将 rows 写入名为 sheetName 的工作表，所有单元格按文本写入
+func Write(w io.Writer, sheetName string, rows [][]string) error {
+	sw, err := NewStreamWriter(w, sheetName)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		cells := make([]Cell, len(row))
+		for i, value := range row {
+			cells[i] = String(value)
+		}
+		if err := sw.WriteRow(cells); err != nil {
+			return err
+		}
+	}
+	return sw.Close()
+}
+
+// Cell 单元格，Numeric 为 true 时按数字写入，Date 为 true 时数字按日期格式显示
+type Cell struct {
+	Value   string
+	Numeric bool
+	Date    bool
+}
+
+// String 文本单元格
+func String(value string) Cell {
+	return Cell{Value: value}
+}
+
+// Number 数字单元格
+func Number(value float64) Cell {
+	return Cell{Value: strconv.FormatFloat(value, 'f', -1, 64), Numeric: true}
+}
+
+// Date 日期单元格，按 t 所在时区的时间写为 Excel 日期序列号
+func Date(t time.Time) Cell {
+	_, offset := t.Zone()
+	// Excel 日期序列号以 1899-12-30 为 0，unix 零点为 25569
+	serial := float64(t.Unix()+int64(offset))/86400 + 25569
+	return Cell{Value: strconv.FormatFloat(serial, 'f', -1, 64), Numeric: true, Date: true}
+}
+
+// StreamWriter 逐行写入单个工作表，不在内存中保留已写入的行
+type StreamWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+	buf   strings.Builder
+}
+
+// NewStreamWriter 写入工作簿结构并打开工作表，写完后必须调用 Close
+func NewStreamWriter(w io.Writer, sheetName string) (*StreamWriter, error) {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{workbookPath, fmt.Sprintf(workbookXML, escape(sheetName))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{stylesPath, fmt.Sprintf(stylesXML, escape(DateFormat))},
+	}
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s failed: %w", file.name, err)
+		}
+		if _, err := io.WriteString(fw, file.content); err != nil {
+			return nil, fmt.Errorf("write %s failed: %w", file.name, err)
+		}
+	}
+	sheet, err := zw.Create(firstSheetPath)
+	if err != nil {
+		return nil, fmt.Errorf("create %s failed: %w", firstSheetPath, err)
+	}
+	if _, err := io.WriteString(sheet, sheetHeaderXML); err != nil {
+		return nil, fmt.Errorf("write %s failed: %w", firstSheetPath, err)
+	}
+	return &StreamWriter{zw: zw, sheet: sheet}, nil
+}
+
+// WriteRow 写入下一行，空文本单元格会被跳过
+func (sw *StreamWriter) WriteRow(cells []Cell) error {
+	sw.row++
+	sw.buf.Reset()
+	fmt.Fprintf(&sw.buf, `<row r="%d">`, sw.row)
+	for c, cell := range cells {
+		if cell.Value == "" {
+			continue
+		}
+		if cell.Date {
+			fmt.Fprintf(&sw.buf, `<c r="%s%d" s="%d"><v>%s</v></c>`, columnName(c), sw.row, dateStyleIndex, escape(cell.Value))
+			continue
+		}
+		if cell.Numeric {
+			fmt.Fprintf(&sw.buf, `<c r="%s%d"><v>%s</v></c>`, columnName(c), sw.row, escape(cell.Value))
+			continue
+		}
+		fmt.Fprintf(&sw.buf, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+			columnName(c), sw.row, escape(cell.Value))
+	}
+	sw.buf.WriteString(`</row>`)
+	if _, err := io.WriteString(sw.sheet, sw.buf.String()); err != nil {
+		return fmt.Errorf("write row %d failed: %w", sw.row, err)
+	}
+	return nil
+}
+
+// Close 结束工作表并写入 zip 目录，不会关闭底层 writer
+func (sw *StreamWriter) Close() error {
+	if _, err := io.WriteString(sw.sheet, sheetFooterXML); err != nil {
+		return fmt.Errorf("write %s failed: %w", firstSheetPath, err)
+	}
+	return sw.zw.Close()
+}
+
+// Read 读取第一个工作表的全部行，空单元格返回空字符串
+func Read(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("open xlsx failed: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	var shared []string
+	if file, ok := files[sharedStringsPath]; ok {
+		var sst struct {
+			Items []struct {
+				Text string `xml:"t"`
+				Runs []struct {
+					Text string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"si"`
+		}
+		if err := decodeZipXML(file, &sst); err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	file, ok := files[firstSheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx 中没有工作表")
+	}
+	var sheet struct {
+		Rows []struct {
+			Index int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string `xml:"t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		// 补齐被跳过的空行
+		for row.Index > len(rows)+1 {
+			rows = append(rows, nil)
+		}
+		var values []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				if parsed, err := columnIndex(cell.Ref); err == nil {
+					col = parsed
+				}
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("单元格 %s 的共享字符串索引无效: %s", cell.Ref, cell.Value)
+				}
+				value = shared[idx]
+			case "inlineStr":
+				value = cell.Inline.Text
+			}
+			values = append(values, value)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// ReadBytes 读取内存中的 xlsx 内容
+func ReadBytes(data []byte) ([][]string, error) {
+	return Read(bytes.NewReader(data), int64(len(data)))
+}
+
+func decodeZipXML(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("open %s failed: %w", file.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("decode %s failed: %w", file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex 将 "C12" 之类的单元格引用转换为从 0 开始的列号
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for i, ch := range ref {
+		if ch >= 'A' && ch <= 'Z' {
+			col = col*26 + int(ch-'A'+1)
+			continue
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid cell ref %s", ref)
+		}
+		break
+	}
+	return col - 1, nil
+}
+
+// columnName 将从 0 开始的列号转换为 "A"、"AB" 等列名
+func columnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+const sheetHeaderXML = xml.Header +
+	`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetFooterXML = `</sheetData></worksheet>`
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+const contentTypesXML = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookXML = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const workbookRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// stylesXML 第 0 个样式为默认样式，第 1 个为日期格式
+const stylesXML = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="%s"/></numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
@@ -0,0 +1,89 @@
+package xlsx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteRead(t *testing.T) {
+	rows := [][]string{
+		{"product_id", "days_to_ship", "备注"},
+		{"1", "", "a & <b>"},
+		{},
+		{"3", "7"},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "Sheet1", rows); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := ReadBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := [][]string{
+		{"product_id", "days_to_ship", "备注"},
+		{"1", "", "a & <b>"},
+		nil,
+		{"3", "7"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+	if columnName(27) != "AB" {
+		t.Errorf("columnName(27) = %s", columnName(27))
+	}
+}
+
+func TestStreamWriterNumber(t *testing.T) {
+	var buf bytes.Buffer
+	sw, err := NewStreamWriter(&buf, "Export")
+	if err != nil {
+		t.Fatalf("NewStreamWriter() error = %v", err)
+	}
+	if err := sw.WriteRow([]Cell{String("sold"), String("price")}); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := sw.WriteRow([]Cell{Number(12), Number(19.9)}); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	got, err := ReadBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := [][]string{{"sold", "price"}, {"12", "19.9"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+}
+
+func TestStreamWriterDate(t *testing.T) {
+	var buf bytes.Buffer
+	sw, err := NewStreamWriter(&buf, "Export")
+	if err != nil {
+		t.Fatalf("NewStreamWriter() error = %v", err)
+	}
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	if err := sw.WriteRow([]Cell{Date(created)}); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "styles.xml") {
+		t.Error("workbook should contain styles.xml")
+	}
+	got, err := ReadBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	// 2024-05-01 为 45413，12:00 为 0.5
+	if want := [][]string{{"45413.5"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+}
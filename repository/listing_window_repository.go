@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/global"
+	"github.com/donghui12/shopee_tool_base/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// listingWindowItemBatchSize 窗口商品批量写入的条数
+const listingWindowItemBatchSize = 500
+
+type ListingWindowRepository struct {
+	db *gorm.DB
+}
+
+func NewListingWindowRepository() *ListingWindowRepository {
+	return &ListingWindowRepository{db: global.DB}
+}
+
+// CreateListingWindow 保存窗口及其商品
+func (r *ListingWindowRepository) CreateListingWindow(window *model.ListingWindow, items []model.ListingWindowItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(window).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		for i := range items {
+			items[i].WindowID = window.ID
+		}
+		return tx.CreateInBatches(items, listingWindowItemBatchSize).Error
+	})
+}
+
+// ListDueListingWindows 获取已到开始时间待执行、或已到结束时间待恢复的窗口
+func (r *ListingWindowRepository) ListDueListingWindows(now time.Time) ([]model.ListingWindow, error) {
+	var windows []model.ListingWindow
+	err := r.db.Where("(status = ? AND start_at <= ?) OR (status = ? AND end_at <= ?)",
+		model.ListingWindowStatusScheduled, now, model.ListingWindowStatusActive, now).
+		Order("start_at").Find(&windows).Error
+	return windows, err
+}
+
+// ListListingWindows 按开始时间倒序获取店铺的窗口
+func (r *ListingWindowRepository) ListListingWindows(shopID string, limit int) ([]model.ListingWindow, error) {
+	var windows []model.ListingWindow
+	err := r.db.Where("shop_id = ?", shopID).Order("start_at DESC").Limit(limit).Find(&windows).Error
+	return windows, err
+}
+
+// GetListingWindowItems 获取窗口的商品
+func (r *ListingWindowRepository) GetListingWindowItems(windowID int64) ([]model.ListingWindowItem, error) {
+	var items []model.ListingWindowItem
+	err := r.db.Where("window_id = ?", windowID).Order("product_id").Find(&items).Error
+	return items, err
+}
+
+// SaveListingWindowItems 按 window_id + product_id 写入商品状态
+func (r *ListingWindowRepository) SaveListingWindowItems(items []model.ListingWindowItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "window_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "reason", "updated_at"}),
+	}).CreateInBatches(items, listingWindowItemBatchSize).Error
+}
+
+// UpdateListingWindow 更新窗口的状态、执行与恢复时间
+func (r *ListingWindowRepository) UpdateListingWindow(window *model.ListingWindow) error {
+	return r.db.Model(window).Select("status", "applied_at", "reverted_at", "error").Updates(window).Error
+}
+
+// CancelListingWindow 取消尚未执行的窗口，返回是否取消成功
+func (r *ListingWindowRepository) CancelListingWindow(id int64) (bool, error) {
+	result := r.db.Model(&model.ListingWindow{}).
+		Where("id = ? AND status = ?", id, model.ListingWindowStatusScheduled).
+		Update("status", model.ListingWindowStatusCanceled)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/global"
+	"github.com/donghui12/shopee_tool_base/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductDeletionRepository struct {
+	db *gorm.DB
+}
+
+func NewProductDeletionRepository() *ProductDeletionRepository {
+	return &ProductDeletionRepository{db: global.DB}
+}
+
+// AddProtectedProducts 将商品加入保护列表，已存在时更新原因
+func (r *ProductDeletionRepository) AddProtectedProducts(shopID, reason string, productIDs []int64) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	products := make([]model.ProtectedProduct, 0, len(productIDs))
+	for _, productID := range productIDs {
+		products = append(products, model.ProtectedProduct{ShopID: shopID, ProductID: productID, Reason: reason})
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "shop_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason"}),
+	}).CreateInBatches(products, productBatchSize).Error
+}
+
+// RemoveProtectedProducts 将商品移出保护列表
+func (r *ProductDeletionRepository) RemoveProtectedProducts(shopID string, productIDs []int64) error {
+	return r.db.Where("shop_id = ? AND product_id IN ?", shopID, productIDs).
+		Delete(&model.ProtectedProduct{}).Error
+}
+
+// ListProtectedProductIds 获取店铺受保护的商品 id
+func (r *ProductDeletionRepository) ListProtectedProductIds(shopID string) ([]int64, error) {
+	var productIDs []int64
+	err := r.db.Model(&model.ProtectedProduct{}).Where("shop_id = ?", shopID).Pluck("product_id", &productIDs).Error
+	return productIDs, err
+}
+
+// CountDeletionsSince 统计店铺 since 之后已发送删除的商品数(不含删除失败的)
+func (r *ProductDeletionRepository) CountDeletionsSince(shopID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ProductDeletion{}).
+		Where("shop_id = ? AND created_at >= ? AND status <> ?", shopID, since, model.ProductDeletionStatusFailed).
+		Count(&count).Error
+	return count, err
+}
+
+// ArchiveDeletions 保存删除前的商品记录
+func (r *ProductDeletionRepository) ArchiveDeletions(records []model.ProductDeletion) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(records, productBatchSize).Error
+}
+
+// UpdateDeletionStatus 更新一次删除请求中部分商品的状态
+func (r *ProductDeletionRepository) UpdateDeletionStatus(requestID string, productIDs []int64, status, reason string) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.ProductDeletion{}).
+		Where("request_id = ? AND product_id IN ?", requestID, productIDs).
+		Updates(map[string]interface{}{"status": status, "reason": reason}).Error
+}
+
+// ListDeletions 按时间倒序获取店铺的删除归档
+func (r *ProductDeletionRepository) ListDeletions(shopID string, limit int) ([]model.ProductDeletion, error) {
+	var records []model.ProductDeletion
+	err := r.db.Where("shop_id = ?", shopID).Order("id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/global"
+	"github.com/donghui12/shopee_tool_base/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// productBatchSize 商品与规格批量写入的条数
+const productBatchSize = 500
+
+type ProductRepository struct {
+	db *gorm.DB
+}
+
+func NewProductRepository() *ProductRepository {
+	return &ProductRepository{db: global.DB}
+}
+
+// UpsertProducts 按 shop_id + product_id 写入商品，并用 models 替换这些商品的规格
+func (r *ProductRepository) UpsertProducts(products []model.Product, models []model.ProductModel) error {
+	if len(products) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "shop_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"region", "name", "status", "days_to_ship", "pre_order",
+				"liked_count", "sold_count", "view_count", "model_count", "create_time", "last_synced_at",
+				"removed_at", "updated_at"}),
+		}).CreateInBatches(products, productBatchSize).Error
+		if err != nil {
+			return err
+		}
+
+		productIDs := make([]int64, 0, len(products))
+		for _, product := range products {
+			productIDs = append(productIDs, product.ProductID)
+		}
+		for start := 0; start < len(productIDs); start += productBatchSize {
+			end := start + productBatchSize
+			if end > len(productIDs) {
+				end = len(productIDs)
+			}
+			if err := tx.Where("shop_id = ? AND product_id IN ?", products[0].ShopID, productIDs[start:end]).
+				Delete(&model.ProductModel{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(models, productBatchSize).Error
+	})
+}
+
+// MarkMissingAsDeleted 将店铺中 syncedAt 之前同步、本次未出现的商品标记为已删除，返回标记的数量
+func (r *ProductRepository) MarkMissingAsDeleted(shopID string, syncedAt time.Time) (int64, error) {
+	result := r.db.Model(&model.Product{}).
+		Where("shop_id = ? AND status <> ? AND last_synced_at < ?", shopID, model.ProductStatusDeleted, syncedAt).
+		Updates(map[string]interface{}{
+			"status":     model.ProductStatusDeleted,
+			"removed_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetProduct 获取店铺中的单个商品
+func (r *ProductRepository) GetProduct(shopID string, productID int64) (*model.Product, error) {
+	var product model.Product
+	err := r.db.Where("shop_id = ? AND product_id = ?", shopID, productID).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ListProducts 获取店铺商品，statuses 为空时返回全部状态
+func (r *ProductRepository) ListProducts(shopID string, statuses ...string) ([]model.Product, error) {
+	var products []model.Product
+	query := r.db.Where("shop_id = ?", shopID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("product_id").Find(&products).Error
+	return products, err
+}
+
+// GetProductModels 获取商品的规格
+func (r *ProductRepository) GetProductModels(shopID string, productID int64) ([]model.ProductModel, error) {
+	var models []model.ProductModel
+	err := r.db.Where("shop_id = ? AND product_id = ?", shopID, productID).Order("model_id").Find(&models).Error
+	return models, err
+}
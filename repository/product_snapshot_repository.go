@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/global"
+	"github.com/donghui12/shopee_tool_base/model"
+	"gorm.io/gorm"
+)
+
+// snapshotItemBatchSize 快照明细批量写入的条数
+const snapshotItemBatchSize = 500
+
+type ProductSnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewProductSnapshotRepository() *ProductSnapshotRepository {
+	return &ProductSnapshotRepository{db: global.DB}
+}
+
+// CreateSnapshot 在事务中分配店铺内的下一个版本号并保存快照及明细
+func (r *ProductSnapshotRepository) CreateSnapshot(snapshot *model.ProductSnapshot, items []model.ProductSnapshotItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var version int
+		if err := tx.Model(&model.ProductSnapshot{}).
+			Where("shop_id = ?", snapshot.ShopID).
+			Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+			return err
+		}
+		snapshot.Version = version + 1
+		snapshot.ItemCount = len(items)
+		snapshot.Status = model.ProductSnapshotStatusCreated
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		for i := range items {
+			items[i].SnapshotID = snapshot.ID
+		}
+		return tx.CreateInBatches(items, snapshotItemBatchSize).Error
+	})
+}
+
+// GetSnapshot 获取快照及明细
+func (r *ProductSnapshotRepository) GetSnapshot(id int64) (*model.ProductSnapshot, []model.ProductSnapshotItem, error) {
+	var snapshot model.ProductSnapshot
+	if err := r.db.First(&snapshot, id).Error; err != nil {
+		return nil, nil, err
+	}
+	var items []model.ProductSnapshotItem
+	err := r.db.Where("snapshot_id = ?", id).Find(&items).Error
+	return &snapshot, items, err
+}
+
+// ListSnapshots 按版本倒序获取店铺的快照
+func (r *ProductSnapshotRepository) ListSnapshots(shopID string, limit int) ([]model.ProductSnapshot, error) {
+	var snapshots []model.ProductSnapshot
+	err := r.db.Where("shop_id = ?", shopID).Order("version DESC").Limit(limit).Find(&snapshots).Error
+	return snapshots, err
+}
+
+// MarkRolledBack 标记快照已回滚
+func (r *ProductSnapshotRepository) MarkRolledBack(id int64) error {
+	now := time.Now()
+	return r.db.Model(&model.ProductSnapshot{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":         model.ProductSnapshotStatusRolledBack,
+			"rolled_back_at": &now,
+		}).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/donghui12/shopee_tool_base/global"
+	"github.com/donghui12/shopee_tool_base/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductStatSnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewProductStatSnapshotRepository() *ProductStatSnapshotRepository {
+	return &ProductStatSnapshotRepository{db: global.DB}
+}
+
+// SaveStatSnapshots 按 shop_id + product_id + snapshot_date 写入统计，当天已有记录时覆盖
+func (r *ProductStatSnapshotRepository) SaveStatSnapshots(snapshots []model.ProductStatSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "shop_id"}, {Name: "product_id"}, {Name: "snapshot_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"liked_count", "sold_count", "view_count", "updated_at"}),
+	}).CreateInBatches(snapshots, productBatchSize).Error
+}
+
+// ListStatSnapshots 获取店铺在 [from, to] 日期内的统计，按商品与日期排序
+func (r *ProductStatSnapshotRepository) ListStatSnapshots(shopID, from, to string) ([]model.ProductStatSnapshot, error) {
+	var snapshots []model.ProductStatSnapshot
+	err := r.db.Where("shop_id = ? AND snapshot_date BETWEEN ? AND ?", shopID, from, to).
+		Order("product_id, snapshot_date").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// DeleteStatSnapshotsBefore 清理 date 之前的统计
+func (r *ProductStatSnapshotRepository) DeleteStatSnapshotsBefore(date string) (int64, error) {
+	result := r.db.Where("snapshot_date < ?", date).Delete(&model.ProductStatSnapshot{})
+	return result.RowsAffected, result.Error
+}
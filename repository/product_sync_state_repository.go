@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/global"
+	"github.com/donghui12/shopee_tool_base/model"
+	"gorm.io/gorm"
+)
+
+type ProductSyncStateRepository struct {
+	db *gorm.DB
+}
+
+func NewProductSyncStateRepository() *ProductSyncStateRepository {
+	return &ProductSyncStateRepository{db: global.DB}
+}
+
+// GetHighWaterMark 获取店铺已同步到的 update_time，未同步过返回 0
+func (r *ProductSyncStateRepository) GetHighWaterMark(shopID string) (int64, error) {
+	var state model.ProductSyncState
+	err := r.db.Where("shop_id = ?", shopID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return state.LastUpdateTime, err
+}
+
+// SaveHighWaterMark 保存店铺同步进度
+func (r *ProductSyncStateRepository) SaveHighWaterMark(shopID string, lastUpdateTime int64) error {
+	state := model.ProductSyncState{
+		ShopID:         shopID,
+		LastUpdateTime: lastUpdateTime,
+		LastSyncAt:     time.Now(),
+	}
+	return r.db.Where("shop_id = ?", shopID).
+		Assign(state).FirstOrCreate(&state).Error
+}
@@ -34,6 +34,25 @@ func (s *ShopeeAccountRepository) RefreshToken(id int64, accessToken, refreshTok
 		}).Error
 }
 
+// RefreshTokenIfMatch 仅当 refresh_token 仍为 oldRefreshToken 时更新 token，避免并发刷新互相覆盖
+func (s *ShopeeAccountRepository) RefreshTokenIfMatch(id int64, oldRefreshToken, accessToken, refreshToken, expireTime string) (bool, error) {
+	result := s.db.Model(&model.ShopeeAccount{}).
+		Where("id = ? AND refresh_token = ?", id, oldRefreshToken).
+		Updates(map[string]interface{}{
+			"access_token":  accessToken,
+			"refresh_token": refreshToken,
+			"expired_at":    expireTime,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetShopeeAccountByShopId 根据店铺 id 获取账户
+func (s *ShopeeAccountRepository) GetShopeeAccountByShopId(shopId string) (*model.ShopeeAccount, error) {
+	var account model.ShopeeAccount
+	err := s.db.Where("shop_id = ?", shopId).First(&account).Error
+	return &account, err
+}
+
 // 创建 shopee tw 账户
 func (s *ShopeeAccountRepository) CreateShopeeAccount(shopId, accessToken, refreshToken, expireAt string) error {
 	// 创建账户
@@ -42,6 +61,7 @@ func (s *ShopeeAccountRepository) CreateShopeeAccount(shopId, accessToken, refre
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiredAt:    expireAt,
+		Status:       model.ShopeeAccountStatusActive,
 	}
 	// 保存到数据库, 如果账户已存在则更新
 	var existingShopeeAccount model.ShopeeAccount
@@ -50,6 +70,7 @@ func (s *ShopeeAccountRepository) CreateShopeeAccount(shopId, accessToken, refre
 		existingShopeeAccount.AccessToken = accessToken
 		existingShopeeAccount.RefreshToken = refreshToken
 		existingShopeeAccount.ExpiredAt = expireAt
+		existingShopeeAccount.Status = model.ShopeeAccountStatusActive
 		return s.db.Save(&existingShopeeAccount).Error
 	}
 	// 创建账户
@@ -60,6 +81,13 @@ func (s *ShopeeAccountRepository) CreateShopeeAccount(shopId, accessToken, refre
 	return nil
 }
 
+// DeactivateShopeeAccount 店铺取消授权后标记为失效
+func (s *ShopeeAccountRepository) DeactivateShopeeAccount(shopId string) error {
+	return s.db.Model(&model.ShopeeAccount{}).
+		Where("shop_id = ?", shopId).
+		Update("status", model.ShopeeAccountStatusInactive).Error
+}
+
 // 判断是否授权
 func (s *ShopeeAccountRepository) IsAuth(shopId string) (string, error) {
 	var accessToken string
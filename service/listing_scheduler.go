@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+const (
+	// ListingWindowTimeLayout 窗口起止时间的格式，按店铺区域的当地时间解析
+	ListingWindowTimeLayout = "2006-01-02 15:04"
+	// DefaultListingSchedulerInterval 后台巡检间隔
+	DefaultListingSchedulerInterval = time.Minute
+)
+
+// ListingWindowStore 定时上下架窗口存储
+type ListingWindowStore interface {
+	CreateListingWindow(window *model.ListingWindow, items []model.ListingWindowItem) error
+	ListDueListingWindows(now time.Time) ([]model.ListingWindow, error)
+	GetListingWindowItems(windowID int64) ([]model.ListingWindowItem, error)
+	SaveListingWindowItems(items []model.ListingWindowItem) error
+	UpdateListingWindow(window *model.ListingWindow) error
+	CancelListingWindow(id int64) (bool, error)
+}
+
+// ShopCookieSource 获取 CNSC 店铺当前可用的 cookies
+type ShopCookieSource interface {
+	Cookies(ctx context.Context, shopId string) (string, error)
+}
+
+// accountCookieSource 通过店铺所属账号获取 cookies
+type accountCookieSource struct {
+	shops    *repository.ShopRepository
+	accounts *repository.AccountRepository
+}
+
+func (s accountCookieSource) Cookies(ctx context.Context, shopId string) (string, error) {
+	shop, err := s.shops.GetShopByID(shopId)
+	if err != nil {
+		return "", fmt.Errorf("获取店铺 %s 失败: %w", shopId, err)
+	}
+	account, err := s.accounts.GetAccountByID(uint(shop.AccountId))
+	if err != nil {
+		return "", fmt.Errorf("获取店铺 %s 的账号失败: %w", shopId, err)
+	}
+	if account.Cookies == "" {
+		return "", fmt.Errorf("店铺 %s 没有可用的 cookies", shopId)
+	}
+	return account.Cookies, nil
+}
+
+// ListingWindowRequest 创建窗口的参数，Start/End 为店铺区域的当地时间
+type ListingWindowRequest struct {
+	ShopId      string
+	Region      string
+	Name        string
+	Action      string  // model.ListingWindowActionUnlist 或 model.ListingWindowActionList
+	ProductIds  []int64 // 为空时需要设置 AllProducts
+	AllProducts bool
+	Start       string // ListingWindowTimeLayout 格式
+	End         string
+}
+
+// ListingScheduler 在窗口开始时上下架商品，结束时只恢复本窗口修改过的商品
+type ListingScheduler struct {
+	client   *shopee.Client
+	store    ListingWindowStore
+	cookies  ShopCookieSource
+	interval time.Duration
+}
+
+// ListingSchedulerOption ListingScheduler 配置项
+type ListingSchedulerOption func(*ListingScheduler)
+
+// WithListingWindowStore 设置窗口存储
+func WithListingWindowStore(store ListingWindowStore) ListingSchedulerOption {
+	return func(s *ListingScheduler) {
+		s.store = store
+	}
+}
+
+// WithShopCookieSource 设置 cookies 来源
+func WithShopCookieSource(cookies ShopCookieSource) ListingSchedulerOption {
+	return func(s *ListingScheduler) {
+		s.cookies = cookies
+	}
+}
+
+// WithListingSchedulerInterval 设置后台巡检间隔
+func WithListingSchedulerInterval(interval time.Duration) ListingSchedulerOption {
+	return func(s *ListingScheduler) {
+		s.interval = interval
+	}
+}
+
+// NewListingScheduler 创建定时上下架调度器，未设置的存储与 cookies 来源使用数据库
+func NewListingScheduler(client *shopee.Client, opts ...ListingSchedulerOption) *ListingScheduler {
+	s := &ListingScheduler{client: client, interval: DefaultListingSchedulerInterval}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.store == nil {
+		s.store = repository.NewListingWindowRepository()
+	}
+	if s.cookies == nil {
+		s.cookies = accountCookieSource{shops: repository.NewShopRepository(), accounts: repository.NewAccountRepository()}
+	}
+	return s
+}
+
+// Schedule 校验并保存窗口
+func (s *ListingScheduler) Schedule(req ListingWindowRequest) (*model.ListingWindow, error) {
+	if req.Action != model.ListingWindowActionUnlist && req.Action != model.ListingWindowActionList {
+		return nil, fmt.Errorf("未知的窗口动作 %s", req.Action)
+	}
+	if len(req.ProductIds) == 0 && !req.AllProducts {
+		return nil, fmt.Errorf("窗口需要指定商品或作用于全部商品")
+	}
+	loc, err := shopee.LocationOfRegion(req.Region)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.ParseInLocation(ListingWindowTimeLayout, req.Start, loc)
+	if err != nil {
+		return nil, fmt.Errorf("开始时间格式错误: %w", err)
+	}
+	end, err := time.ParseInLocation(ListingWindowTimeLayout, req.End, loc)
+	if err != nil {
+		return nil, fmt.Errorf("结束时间格式错误: %w", err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("结束时间必须晚于开始时间")
+	}
+	if !end.After(time.Now()) {
+		return nil, fmt.Errorf("结束时间已过")
+	}
+
+	window := &model.ListingWindow{
+		ShopID:      req.ShopId,
+		Region:      req.Region,
+		Name:        req.Name,
+		Action:      req.Action,
+		AllProducts: req.AllProducts,
+		StartAt:     start.UTC(),
+		EndAt:       end.UTC(),
+		Status:      model.ListingWindowStatusScheduled,
+	}
+	var items []model.ListingWindowItem
+	if !req.AllProducts {
+		seen := make(map[int64]bool, len(req.ProductIds))
+		for _, productId := range req.ProductIds {
+			if seen[productId] {
+				continue
+			}
+			seen[productId] = true
+			items = append(items, model.ListingWindowItem{ProductID: productId, Status: model.ListingWindowItemStatusPending})
+		}
+	}
+	if err := s.store.CreateListingWindow(window, items); err != nil {
+		return nil, fmt.Errorf("保存窗口失败: %w", err)
+	}
+	return window, nil
+}
+
+// Cancel 取消尚未开始执行的窗口，已执行的窗口仍会在结束时恢复
+func (s *ListingScheduler) Cancel(id int64) error {
+	ok, err := s.store.CancelListingWindow(id)
+	if err != nil {
+		return fmt.Errorf("取消窗口失败: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("窗口 %d 不存在或已开始执行", id)
+	}
+	return nil
+}
+
+// Tick 执行 now 时刻到期的窗口，单个窗口失败只记录在窗口上，下次巡检重试
+func (s *ListingScheduler) Tick(ctx context.Context, now time.Time) error {
+	windows, err := s.store.ListDueListingWindows(now)
+	if err != nil {
+		return fmt.Errorf("获取到期窗口失败: %w", err)
+	}
+	for i := range windows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		window := &windows[i]
+		switch {
+		case window.Status == model.ListingWindowStatusScheduled && !now.Before(window.EndAt):
+			err = s.expire(ctx, window, now)
+		case window.Status == model.ListingWindowStatusScheduled:
+			err = s.apply(ctx, window, now)
+		default:
+			err = s.revert(ctx, window, now)
+		}
+		if err != nil {
+			logger.Error("执行上下架窗口失败", zap.Int64("window_id", window.ID), zap.String("shop_id", window.ShopID), zap.Error(err))
+			window.Error = err.Error()
+		}
+		if err := s.store.UpdateListingWindow(window); err != nil {
+			logger.Error("更新上下架窗口失败", zap.Int64("window_id", window.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Run 按巡检间隔在后台执行到期窗口，直到 ctx 结束
+func (s *ListingScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Error("上下架窗口巡检失败", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply 修改当前不处于目标状态的商品，并记录实际修改过的商品
+func (s *ListingScheduler) apply(ctx context.Context, window *model.ListingWindow, now time.Time) error {
+	cnsc, err := s.productService(ctx, window)
+	if err != nil {
+		return err
+	}
+	// 下架窗口只处理在售商品，上架窗口只处理已下架商品
+	listType := shopee.ListTypeLive
+	if window.Action == model.ListingWindowActionList {
+		listType = shopee.ListTypeDelisted
+	}
+	products, err := s.client.GetProductDetailListWithDayToShip(cnsc.cookies, cnsc.shopId, cnsc.region, listType)
+	if err != nil {
+		return fmt.Errorf("获取商品列表失败, list_type=%s: %w", listType, err)
+	}
+	changeable := make(map[int64]bool, len(products))
+	for _, product := range products {
+		changeable[int64(product.ID)] = true
+	}
+
+	var items []model.ListingWindowItem
+	if window.AllProducts {
+		for _, product := range products {
+			items = append(items, model.ListingWindowItem{
+				WindowID:  window.ID,
+				ProductID: int64(product.ID),
+				Status:    model.ListingWindowItemStatusPending,
+			})
+		}
+	} else if items, err = s.store.GetListingWindowItems(window.ID); err != nil {
+		return fmt.Errorf("获取窗口商品失败: %w", err)
+	}
+
+	var targets []int64
+	for i := range items {
+		if items[i].Status == model.ListingWindowItemStatusApplied {
+			// 上次执行中断前已修改的商品
+			continue
+		}
+		if changeable[items[i].ProductID] {
+			targets = append(targets, items[i].ProductID)
+			continue
+		}
+		items[i].Status = model.ListingWindowItemStatusSkipped
+	}
+	result, err := cnsc.SetListed(ctx, targets, window.Action == model.ListingWindowActionList)
+	if err != nil && result == nil {
+		return err
+	}
+	s.markItems(items, result, model.ListingWindowItemStatusApplied, model.ListingWindowItemStatusFailed)
+	if err := s.store.SaveListingWindowItems(items); err != nil {
+		// 未记录修改的商品无法恢复，保留 scheduled 状态等待下次重试
+		return fmt.Errorf("保存窗口商品失败: %w", err)
+	}
+	if err != nil {
+		// 执行中断，已修改的商品已记录，下次巡检继续
+		return err
+	}
+
+	window.Status = model.ListingWindowStatusActive
+	window.AppliedAt = &now
+	window.Error = ""
+	logger.Info("上下架窗口已执行", zap.Int64("window_id", window.ID), zap.String("shop_id", window.ShopID),
+		zap.String("action", window.Action), zap.Int("changed", len(result.Succeeded)), zap.Int("failed", len(result.Failed)))
+	return nil
+}
+
+// revert 恢复本窗口修改过的商品，恢复失败的商品保留 applied 状态，下次巡检重试
+func (s *ListingScheduler) revert(ctx context.Context, window *model.ListingWindow, now time.Time) error {
+	cnsc, err := s.productService(ctx, window)
+	if err != nil {
+		return err
+	}
+	items, err := s.store.GetListingWindowItems(window.ID)
+	if err != nil {
+		return fmt.Errorf("获取窗口商品失败: %w", err)
+	}
+	var applied []model.ListingWindowItem
+	var targets []int64
+	for _, item := range items {
+		if item.Status == model.ListingWindowItemStatusApplied {
+			applied = append(applied, item)
+			targets = append(targets, item.ProductID)
+		}
+	}
+
+	result, err := cnsc.SetListed(ctx, targets, window.Action != model.ListingWindowActionList)
+	if err != nil && result == nil {
+		return err
+	}
+	s.markItems(applied, result, model.ListingWindowItemStatusReverted, model.ListingWindowItemStatusApplied)
+	if err := s.store.SaveListingWindowItems(applied); err != nil {
+		return fmt.Errorf("保存窗口商品失败: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d 个商品恢复失败", len(result.Failed))
+	}
+
+	window.Status = model.ListingWindowStatusCompleted
+	window.RevertedAt = &now
+	window.Error = ""
+	logger.Info("上下架窗口已恢复", zap.Int64("window_id", window.ID), zap.String("shop_id", window.ShopID),
+		zap.Int("reverted", len(result.Succeeded)))
+	return nil
+}
+
+// expire 处理结束前没有完成执行的窗口，上次执行中断前已修改的商品需要恢复，否则不再修改商品
+func (s *ListingScheduler) expire(ctx context.Context, window *model.ListingWindow, now time.Time) error {
+	items, err := s.store.GetListingWindowItems(window.ID)
+	if err != nil {
+		return fmt.Errorf("获取窗口商品失败: %w", err)
+	}
+	for _, item := range items {
+		if item.Status == model.ListingWindowItemStatusApplied {
+			return s.revert(ctx, window, now)
+		}
+	}
+	window.Status = model.ListingWindowStatusExpired
+	return nil
+}
+
+// markItems 按操作结果更新商品状态，未参与操作的商品保持不变
+func (s *ListingScheduler) markItems(items []model.ListingWindowItem, result *ProductOperationResult, succeeded, failed string) {
+	reasons := make(map[int64]string, len(result.Failed))
+	for _, failure := range result.Failed {
+		reasons[failure.ItemId] = failure.Reason
+	}
+	done := make(map[int64]bool, len(result.Succeeded))
+	for _, itemId := range result.Succeeded {
+		done[itemId] = true
+	}
+	for i := range items {
+		if done[items[i].ProductID] {
+			items[i].Status, items[i].Reason = succeeded, ""
+		} else if reason, ok := reasons[items[i].ProductID]; ok {
+			items[i].Status, items[i].Reason = failed, reason
+		}
+	}
+}
+
+func (s *ListingScheduler) productService(ctx context.Context, window *model.ListingWindow) (*sellerCenterProductService, error) {
+	cookies, err := s.cookies.Cookies(ctx, window.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	return &sellerCenterProductService{client: s.client, cookies: cookies, shopId: window.ShopID, region: window.Region}, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+)
+
+type fakeListingWindowStore struct {
+	windows map[int64]*model.ListingWindow
+	items   map[int64]model.ListingWindowItem
+}
+
+func (s *fakeListingWindowStore) CreateListingWindow(window *model.ListingWindow, items []model.ListingWindowItem) error {
+	window.ID = int64(len(s.windows) + 1)
+	s.windows[window.ID] = window
+	for i := range items {
+		items[i].WindowID = window.ID
+	}
+	return s.SaveListingWindowItems(items)
+}
+
+func (s *fakeListingWindowStore) ListDueListingWindows(now time.Time) ([]model.ListingWindow, error) {
+	var windows []model.ListingWindow
+	for _, window := range s.windows {
+		if (window.Status == model.ListingWindowStatusScheduled && !window.StartAt.After(now)) ||
+			(window.Status == model.ListingWindowStatusActive && !window.EndAt.After(now)) {
+			windows = append(windows, *window)
+		}
+	}
+	return windows, nil
+}
+
+func (s *fakeListingWindowStore) GetListingWindowItems(windowID int64) ([]model.ListingWindowItem, error) {
+	var items []model.ListingWindowItem
+	for productID := int64(1); productID <= 3; productID++ {
+		if item, ok := s.items[productID]; ok && item.WindowID == windowID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (s *fakeListingWindowStore) SaveListingWindowItems(items []model.ListingWindowItem) error {
+	for _, item := range items {
+		s.items[item.ProductID] = item
+	}
+	return nil
+}
+
+func (s *fakeListingWindowStore) UpdateListingWindow(window *model.ListingWindow) error {
+	*s.windows[window.ID] = *window
+	return nil
+}
+
+func (s *fakeListingWindowStore) CancelListingWindow(id int64) (bool, error) {
+	return false, nil
+}
+
+type staticCookieSource string
+
+func (s staticCookieSource) Cookies(ctx context.Context, shopId string) (string, error) {
+	return string(s), nil
+}
+
+func TestListingScheduler(t *testing.T) {
+	var updates [][]shopee.BatchUpdateProductInfoItem
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductDetailList:
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":2},"list":[{"id":1},{"id":2}]}}`)
+		case shopee.APIPathBatchUpdateProductInfo:
+			var req []shopee.BatchUpdateProductInfoItem
+			json.NewDecoder(r.Body).Decode(&req)
+			updates = append(updates, req)
+			if len(req) > 1 {
+				io.WriteString(w, `{"code":0,"data":{"result":[{"id":2,"code":1000601,"user_message":"商品在活动中"}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"result":[]}}`)
+		}
+	})
+	defer server.Close()
+
+	store := &fakeListingWindowStore{windows: make(map[int64]*model.ListingWindow), items: make(map[int64]model.ListingWindowItem)}
+	scheduler := NewListingScheduler(client, WithListingWindowStore(store), WithShopCookieSource(staticCookieSource("SPC_EC=1;")))
+	window, err := scheduler.Schedule(ListingWindowRequest{
+		ShopId:     "100",
+		Region:     "sg",
+		Action:     model.ListingWindowActionUnlist,
+		ProductIds: []int64{1, 2, 3},
+		Start:      "2099-02-01 00:00",
+		End:        "2099-02-03 00:00",
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if want := time.Date(2099, 1, 31, 16, 0, 0, 0, time.UTC); !window.StartAt.Equal(want) {
+		t.Errorf("StartAt = %v, want %v", window.StartAt, want)
+	}
+
+	if err := scheduler.Tick(context.Background(), window.StartAt.Add(-time.Minute)); err != nil || len(updates) != 0 {
+		t.Fatalf("Tick() before start error = %v, updates = %v", err, updates)
+	}
+	if err := scheduler.Tick(context.Background(), window.StartAt); err != nil {
+		t.Fatalf("Tick() at start error = %v", err)
+	}
+	if store.windows[window.ID].Status != model.ListingWindowStatusActive {
+		t.Fatalf("Unexpected window after start: %+v", store.windows[window.ID])
+	}
+	wantStatus := map[int64]string{
+		1: model.ListingWindowItemStatusApplied,
+		2: model.ListingWindowItemStatusFailed,
+		3: model.ListingWindowItemStatusSkipped,
+	}
+	for productID, status := range wantStatus {
+		if store.items[productID].Status != status {
+			t.Errorf("Item %d status = %s, want %s", productID, store.items[productID].Status, status)
+		}
+	}
+
+	if err := scheduler.Tick(context.Background(), window.EndAt); err != nil {
+		t.Fatalf("Tick() at end error = %v", err)
+	}
+	want := [][]shopee.BatchUpdateProductInfoItem{
+		{{ID: 1, Unlisted: true}, {ID: 2, Unlisted: true}},
+		{{ID: 1, Unlisted: false}},
+	}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("updates = %+v, want %+v", updates, want)
+	}
+	if store.windows[window.ID].Status != model.ListingWindowStatusCompleted || store.items[1].Status != model.ListingWindowItemStatusReverted {
+		t.Errorf("Unexpected window after end: %+v, items = %+v", store.windows[window.ID], store.items)
+	}
+}
+
+func TestListingSchedulerExpiredAfterInterruptedApply(t *testing.T) {
+	var updates [][]shopee.BatchUpdateProductInfoItem
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathBatchUpdateProductInfo:
+			var req []shopee.BatchUpdateProductInfoItem
+			json.NewDecoder(r.Body).Decode(&req)
+			updates = append(updates, req)
+			io.WriteString(w, `{"code":0,"data":{"result":[]}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	// 上次执行已修改商品 1，但窗口状态没有保存，之后一直到结束时间才再次巡检
+	store := &fakeListingWindowStore{windows: make(map[int64]*model.ListingWindow), items: make(map[int64]model.ListingWindowItem)}
+	window := &model.ListingWindow{
+		ShopID:  "100",
+		Region:  "sg",
+		Action:  model.ListingWindowActionUnlist,
+		StartAt: time.Date(2099, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndAt:   time.Date(2099, 2, 3, 0, 0, 0, 0, time.UTC),
+		Status:  model.ListingWindowStatusScheduled,
+	}
+	store.CreateListingWindow(window, []model.ListingWindowItem{
+		{ProductID: 1, Status: model.ListingWindowItemStatusApplied},
+		{ProductID: 2, Status: model.ListingWindowItemStatusPending},
+	})
+	scheduler := NewListingScheduler(client, WithListingWindowStore(store), WithShopCookieSource(staticCookieSource("SPC_EC=1;")))
+
+	if err := scheduler.Tick(context.Background(), window.EndAt.Add(time.Hour)); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	want := [][]shopee.BatchUpdateProductInfoItem{{{ID: 1, Unlisted: false}}}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("updates = %+v, want %+v", updates, want)
+	}
+	if store.windows[window.ID].Status != model.ListingWindowStatusCompleted ||
+		store.items[1].Status != model.ListingWindowItemStatusReverted || store.items[2].Status != model.ListingWindowItemStatusPending {
+		t.Errorf("Unexpected window after expiry: %+v, items = %+v", store.windows[window.ID], store.items)
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+// DefaultOAuthStateTTL 授权 state 的默认有效期
+const DefaultOAuthStateTTL = 10 * time.Minute
+
+// OAuthStateStore 保存授权链接中的 state，用于回调时做 CSRF 校验
+type OAuthStateStore interface {
+	// Issue 生成并保存一个新的 state
+	Issue() (string, error)
+	// Consume 校验 state 是否有效，校验后即失效
+	Consume(state string) bool
+}
+
+// ShopeeAccountStore 持久化授权后的 token
+type ShopeeAccountStore interface {
+	CreateShopeeAccount(shopId, accessToken, refreshToken, expireAt string) error
+}
+
+// MemoryStateStore 基于内存的 state 存储，适用于单实例部署
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	states map[string]time.Time
+}
+
+// NewMemoryStateStore 创建内存 state 存储
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	return &MemoryStateStore{
+		ttl:    ttl,
+		states: make(map[string]time.Time),
+	}
+}
+
+// Issue 生成随机 state，同时清理已过期的 state
+func (s *MemoryStateStore) Issue() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate state failed: %w", err)
+	}
+	state := hex.EncodeToString(buf)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, expireAt := range s.states {
+		if now.After(expireAt) {
+			delete(s.states, k)
+		}
+	}
+	s.states[state] = now.Add(s.ttl)
+	return state, nil
+}
+
+// Consume 校验并删除 state
+func (s *MemoryStateStore) Consume(state string) bool {
+	if state == "" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expireAt, ok := s.states[state]
+	if !ok {
+		return false
+	}
+	delete(s.states, state)
+	return time.Now().Before(expireAt)
+}
+
+// OAuthHelper 生成店铺授权链接并处理授权回调
+type OAuthHelper struct {
+	client      *shopee.Client
+	redirectURL string
+	states      OAuthStateStore
+	accounts    ShopeeAccountStore
+}
+
+// OAuthOption OAuthHelper 配置项
+type OAuthOption func(*OAuthHelper)
+
+// WithOAuthStateStore 设置 state 存储
+func WithOAuthStateStore(store OAuthStateStore) OAuthOption {
+	return func(h *OAuthHelper) {
+		h.states = store
+	}
+}
+
+// WithOAuthAccountStore 设置 token 持久化方式
+func WithOAuthAccountStore(store ShopeeAccountStore) OAuthOption {
+	return func(h *OAuthHelper) {
+		h.accounts = store
+	}
+}
+
+// NewOAuthHelper 创建授权辅助工具，redirectURL 为回调处理器对外暴露的地址
+func NewOAuthHelper(client *shopee.Client, redirectURL string, opts ...OAuthOption) *OAuthHelper {
+	h := &OAuthHelper{
+		client:      client,
+		redirectURL: redirectURL,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.states == nil {
+		h.states = NewMemoryStateStore(DefaultOAuthStateTTL)
+	}
+	if h.accounts == nil {
+		h.accounts = repository.NewShopeeAccountRepository()
+	}
+	return h
+}
+
+// AuthorizationURL 生成带 state 的店铺授权链接
+func (h *OAuthHelper) AuthorizationURL() (string, error) {
+	redirect, err := url.Parse(h.redirectURL)
+	if err != nil {
+		return "", fmt.Errorf("解析回调地址失败: %w", err)
+	}
+	state, err := h.states.Issue()
+	if err != nil {
+		return "", err
+	}
+	query := redirect.Query()
+	query.Set("state", state)
+	redirect.RawQuery = query.Encode()
+	return h.client.BuildAuthPartnerURL(redirect.String())
+}
+
+// ServeHTTP 处理授权回调：校验 state，使用 code 换取 token 并保存
+func (h *OAuthHelper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !h.states.Consume(query.Get("state")) {
+		http.Error(w, "授权链接已失效，请重新授权", http.StatusForbidden)
+		return
+	}
+
+	code := query.Get("code")
+	shopId := query.Get("shop_id")
+	if code == "" || shopId == "" {
+		http.Error(w, "缺少 code 或 shop_id", http.StatusBadRequest)
+		return
+	}
+
+	accessToken, refreshToken, expireAt, err := h.client.GetAccessTokenWithAreaTw(shopId, code, "")
+	if err != nil {
+		logger.Error("授权换取 token 失败", zap.String("shop_id", shopId), zap.Error(err))
+		http.Error(w, "换取 token 失败", http.StatusBadGateway)
+		return
+	}
+	if err := h.accounts.CreateShopeeAccount(shopId, accessToken, refreshToken, expireAt); err != nil {
+		logger.Error("保存授权信息失败", zap.String("shop_id", shopId), zap.Error(err))
+		http.Error(w, "保存授权信息失败", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("店铺授权成功", zap.String("shop_id", shopId))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "店铺 %s 授权成功", shopId)
+}
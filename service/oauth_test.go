@@ -0,0 +1,78 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+)
+
+type fakeAccountStore struct {
+	shopId      string
+	accessToken string
+}
+
+func (s *fakeAccountStore) CreateShopeeAccount(shopId, accessToken, refreshToken, expireAt string) error {
+	s.shopId = shopId
+	s.accessToken = accessToken
+	return nil
+}
+
+// newOpenPlatformTestClient 创建指向本地测试服务的 Open Platform 客户端
+func newOpenPlatformTestClient(handler http.HandlerFunc) (*shopee.Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	config := shopee.TaiwanConfig()
+	config.BaseURL = server.URL
+	config.RetryTimes = 0
+	return shopee.NewClientWithConfig(config), server
+}
+
+func TestOAuthHelper(t *testing.T) {
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != shopee.APIPathAuthTokenForTw {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		io.WriteString(w, `{"access_token":"access","refresh_token":"refresh","expire_in":14400}`)
+	})
+	defer server.Close()
+
+	store := &fakeAccountStore{}
+	helper := NewOAuthHelper(client, "https://example.com/callback", WithOAuthAccountStore(store))
+
+	authURL, err := helper.AuthorizationURL()
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	redirect, _ := url.Parse(parsed.Query().Get("redirect"))
+	state := redirect.Query().Get("state")
+	if state == "" {
+		t.Fatal("redirect should carry state")
+	}
+
+	// 伪造的 state 应被拒绝
+	rec := httptest.NewRecorder()
+	helper.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?state=forged&code=c&shop_id=1", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for forged state, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	helper.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?state="+state+"&code=c&shop_id=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.shopId != "1" || store.accessToken != "access" {
+		t.Errorf("Unexpected stored account: %+v", store)
+	}
+
+	// state 只能使用一次
+	rec = httptest.NewRecorder()
+	helper.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?state="+state+"&code=c&shop_id=1", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for reused state, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+// ProductCatalogStore 商品目录存储
+type ProductCatalogStore interface {
+	UpsertProducts(products []model.Product, models []model.ProductModel) error
+	MarkMissingAsDeleted(shopID string, syncedAt time.Time) (int64, error)
+}
+
+// ProductCatalogSyncResult 一次同步的结果
+type ProductCatalogSyncResult struct {
+	ShopId   string    `json:"shop_id"`
+	SyncedAt time.Time `json:"synced_at"`
+	Live     int       `json:"live"`
+	Unlisted int       `json:"unlisted"`
+	Deleted  int64     `json:"deleted"` // 本次新标记为删除的商品
+}
+
+// ProductCatalogSyncer 将 CNSC 店铺的商品目录全量同步到数据库
+type ProductCatalogSyncer struct {
+	client *shopee.Client
+	store  ProductCatalogStore
+}
+
+// NewProductCatalogSyncer 创建目录同步器，store 为空时使用数据库存储
+func NewProductCatalogSyncer(client *shopee.Client, store ProductCatalogStore) *ProductCatalogSyncer {
+	if store == nil {
+		store = repository.NewProductRepository()
+	}
+	return &ProductCatalogSyncer{client: client, store: store}
+}
+
+// Sync 拉取在售与已下架商品并写入数据库，之后将本次未出现的商品标记为删除
+// 任一列表拉取失败(包括部分页面失败)时不写入也不标记；两个列表都为空时视为异常，不标记删除
+func (s *ProductCatalogSyncer) Sync(ctx context.Context, cookies, shopId, region string) (*ProductCatalogSyncResult, error) {
+	// 数据库时间精度为秒，截断后本次写入的商品不会被误判为早于 syncedAt
+	syncedAt := time.Now().Truncate(time.Second)
+	result := &ProductCatalogSyncResult{ShopId: shopId, SyncedAt: syncedAt}
+
+	var products []model.Product
+	var models []model.ProductModel
+	seen := make(map[int64]bool)
+	for _, list := range []struct {
+		listType string
+		status   string
+	}{
+		{shopee.ListTypeLive, model.ProductStatusLive},
+		{shopee.ListTypeDelisted, model.ProductStatusUnlisted},
+	} {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		listed, err := s.client.GetProductDetailList(cookies, shopId, region, list.listType)
+		if err != nil {
+			return nil, fmt.Errorf("获取商品列表失败, list_type=%s: %w", list.listType, err)
+		}
+		details, err := s.client.GetProductDetailListWithDayToShip(cookies, shopId, region, list.listType)
+		if err != nil {
+			return nil, fmt.Errorf("获取商品出货天数失败, list_type=%s: %w", list.listType, err)
+		}
+		detailMap := make(map[int64]shopee.ProductDetail, len(details))
+		for _, detail := range details {
+			detailMap[int64(detail.ID)] = detail
+		}
+
+		for _, item := range listed {
+			productId := int64(item.ID)
+			if productId == 0 || seen[productId] {
+				continue
+			}
+			seen[productId] = true
+			product := model.Product{
+				ShopID:       shopId,
+				Region:       region,
+				ProductID:    productId,
+				Name:         item.Name,
+				Status:       list.status,
+				LikedCount:   item.Statistics.LikedCount,
+				SoldCount:    item.Statistics.SoldCount,
+				ViewCount:    item.Statistics.ViewCount,
+				ModelCount:   len(item.ModelList),
+				CreateTime:   item.CreateTime,
+				LastSyncedAt: syncedAt,
+			}
+			if detail, ok := detailMap[productId]; ok {
+				product.DaysToShip = detail.DaysToShip
+				product.PreOrder = detail.PreOrder
+			}
+			products = append(products, product)
+			for _, m := range item.ModelList {
+				models = append(models, model.ProductModel{
+					ShopID:    shopId,
+					ProductID: productId,
+					ModelID:   int64(m.ID),
+					Name:      m.Name,
+				})
+			}
+			if list.status == model.ProductStatusLive {
+				result.Live++
+			} else {
+				result.Unlisted++
+			}
+		}
+	}
+
+	if err := s.store.UpsertProducts(products, models); err != nil {
+		return nil, fmt.Errorf("保存商品目录失败: %w", err)
+	}
+	if len(products) == 0 {
+		logger.Warn("同步到的商品为空，跳过删除标记", zap.String("shop_id", shopId))
+		return result, nil
+	}
+	deleted, err := s.store.MarkMissingAsDeleted(shopId, syncedAt)
+	if err != nil {
+		return result, fmt.Errorf("标记已删除商品失败: %w", err)
+	}
+	result.Deleted = deleted
+
+	logger.Info("商品目录同步完成", zap.String("shop_id", shopId), zap.Int("live", result.Live),
+		zap.Int("unlisted", result.Unlisted), zap.Int64("deleted", result.Deleted))
+	return result, nil
+}
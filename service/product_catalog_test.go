@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/pool"
+)
+
+type fakeCatalogStore struct {
+	products map[int64]model.Product
+	models   []model.ProductModel
+}
+
+func (s *fakeCatalogStore) UpsertProducts(products []model.Product, models []model.ProductModel) error {
+	for _, product := range products {
+		s.products[product.ProductID] = product
+	}
+	s.models = models
+	return nil
+}
+
+func (s *fakeCatalogStore) MarkMissingAsDeleted(shopID string, syncedAt time.Time) (int64, error) {
+	var count int64
+	for id, product := range s.products {
+		if product.ShopID == shopID && product.Status != model.ProductStatusDeleted && product.LastSyncedAt.Before(syncedAt) {
+			product.Status = model.ProductStatusDeleted
+			s.products[id] = product
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestProductCatalogSyncer(t *testing.T) {
+	pool.InitWorkerPool()
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		live := r.URL.Query().Get("list_type") == shopee.ListTypeLive
+		switch r.URL.Path {
+		case shopee.APIPathProductList:
+			if live {
+				io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":1,"name":"T恤","create_time":1700000000,`+
+					`"statistics":{"sold_count":5},"model_list":[{"id":11,"name":"S"},{"id":12,"name":"M"}]}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":2,"name":"帽子"}]}}`)
+		case shopee.APIPathProductDetailList:
+			if live {
+				io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":1,"days_to_ship":7,"pre_order":true}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":2,"days_to_ship":2}]}}`)
+		}
+	})
+	defer server.Close()
+
+	store := &fakeCatalogStore{products: map[int64]model.Product{
+		3: {ShopID: "100", ProductID: 3, Status: model.ProductStatusLive, LastSyncedAt: time.Now().Add(-time.Hour)},
+	}}
+	result, err := NewProductCatalogSyncer(client, store).Sync(context.Background(), "SPC_EC=1;", "100", "sg")
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Live != 1 || result.Unlisted != 1 || result.Deleted != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	live := store.products[1]
+	if live.Status != model.ProductStatusLive || live.DaysToShip != 7 || !live.PreOrder || live.SoldCount != 5 || live.ModelCount != 2 {
+		t.Errorf("Unexpected live product: %+v", live)
+	}
+	if store.products[2].Status != model.ProductStatusUnlisted || store.products[3].Status != model.ProductStatusDeleted {
+		t.Errorf("Unexpected statuses: %+v", store.products)
+	}
+	if len(store.models) != 2 || store.models[1].ModelID != 12 {
+		t.Errorf("Unexpected models: %+v", store.models)
+	}
+}
+
+func TestProductCatalogSyncerPageFailure(t *testing.T) {
+	pool.InitWorkerPool()
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductList:
+			if r.URL.Query().Get("page_number") == "2" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":60},"products":[{"id":1,"name":"T恤"}]}}`)
+		case shopee.APIPathProductDetailList:
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":1,"days_to_ship":7}]}}`)
+		}
+	})
+	defer server.Close()
+
+	store := &fakeCatalogStore{products: map[int64]model.Product{
+		3: {ShopID: "100", ProductID: 3, Status: model.ProductStatusLive, LastSyncedAt: time.Now().Add(-time.Hour)},
+	}}
+	if _, err := NewProductCatalogSyncer(client, store).Sync(context.Background(), "SPC_EC=1;", "100", "sg"); err == nil {
+		t.Fatal("Sync() should fail when a page fails")
+	}
+	if len(store.products) != 1 || store.products[3].Status != model.ProductStatusLive {
+		t.Errorf("Nothing should be written or marked deleted, got %+v", store.products)
+	}
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+// 默认删除限制
+const (
+	DefaultDeleteMaxPerCall = 200
+	DefaultDeleteMaxPerDay  = 1000
+)
+
+// ErrDeleteLimitExceeded 删除数量超过单次或单日上限
+var ErrDeleteLimitExceeded = errors.New("删除数量超过上限")
+
+// ProductDeletionStore 受保护商品与删除归档存储
+type ProductDeletionStore interface {
+	ListProtectedProductIds(shopID string) ([]int64, error)
+	CountDeletionsSince(shopID string, since time.Time) (int64, error)
+	ArchiveDeletions(records []model.ProductDeletion) error
+	UpdateDeletionStatus(requestID string, productIDs []int64, status, reason string) error
+}
+
+// ProductDeleteLimits 删除限制，<= 0 的字段使用默认值
+type ProductDeleteLimits struct {
+	MaxPerCall int // 单次请求最多删除的商品数
+	MaxPerDay  int // 单个店铺每天最多删除的商品数
+	BatchSize  int // 单次接口调用提交的商品数
+}
+
+func (l ProductDeleteLimits) withDefaults() ProductDeleteLimits {
+	if l.MaxPerCall <= 0 {
+		l.MaxPerCall = DefaultDeleteMaxPerCall
+	}
+	if l.MaxPerDay <= 0 {
+		l.MaxPerDay = DefaultDeleteMaxPerDay
+	}
+	if l.BatchSize <= 0 {
+		l.BatchSize = cnscBatchSize
+	}
+	return l
+}
+
+// ProductDeleteRequest 删除请求，Auth 不为空时通过 Open Platform 接口删除
+type ProductDeleteRequest struct {
+	Cookies    string
+	Auth       *shopee.OpenAPIAuth
+	ShopId     string
+	Region     string
+	ProductIds []int64
+	Operator   string // 记录在归档中
+	DryRun     bool   // 只返回将要删除的商品，不归档也不删除
+}
+
+// ProductDeleteReport 删除结果
+type ProductDeleteReport struct {
+	RequestId string           `json:"request_id"`
+	DryRun    bool             `json:"dry_run"`
+	Protected []int64          `json:"protected"` // 在保护列表中被跳过的商品
+	NotFound  []int64          `json:"not_found"` // 店铺中不存在的商品
+	Planned   []int64          `json:"planned"`   // 将要删除的商品
+	Deleted   []int64          `json:"deleted"`
+	Failed    []ProductFailure `json:"failed"`
+}
+
+// ProductDeleteGuard 带保护列表、数量限制与删除前归档的商品删除
+type ProductDeleteGuard struct {
+	client *shopee.Client
+	store  ProductDeletionStore
+	limits ProductDeleteLimits
+}
+
+// NewProductDeleteGuard 创建删除保护，store 为空时使用数据库存储
+func NewProductDeleteGuard(client *shopee.Client, store ProductDeletionStore, limits ProductDeleteLimits) *ProductDeleteGuard {
+	if store == nil {
+		store = repository.NewProductDeletionRepository()
+	}
+	return &ProductDeleteGuard{client: client, store: store, limits: limits.withDefaults()}
+}
+
+// Delete 跳过受保护与不存在的商品，检查数量限制，归档商品的完整信息后分批删除
+// 超过上限时整个请求不执行，返回 ErrDeleteLimitExceeded
+func (g *ProductDeleteGuard) Delete(ctx context.Context, req ProductDeleteRequest) (*ProductDeleteReport, error) {
+	report := &ProductDeleteReport{RequestId: uuid.New().String(), DryRun: req.DryRun}
+
+	protectedIds, err := g.store.ListProtectedProductIds(req.ShopId)
+	if err != nil {
+		return nil, fmt.Errorf("获取受保护商品失败: %w", err)
+	}
+	protected := make(map[int64]bool, len(protectedIds))
+	for _, productId := range protectedIds {
+		protected[productId] = true
+	}
+	seen := make(map[int64]bool, len(req.ProductIds))
+	var candidates []int64
+	for _, productId := range req.ProductIds {
+		if seen[productId] {
+			continue
+		}
+		seen[productId] = true
+		if protected[productId] {
+			report.Protected = append(report.Protected, productId)
+			continue
+		}
+		candidates = append(candidates, productId)
+	}
+	if len(candidates) == 0 {
+		return report, nil
+	}
+
+	if len(candidates) > g.limits.MaxPerCall {
+		return nil, fmt.Errorf("%w: 单次删除 %d 个, 上限 %d", ErrDeleteLimitExceeded, len(candidates), g.limits.MaxPerCall)
+	}
+	// 单日数量按店铺所在区域的自然日统计
+	loc, err := shopee.LocationOfRegion(req.Region)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	deletedToday, err := g.store.CountDeletionsSince(req.ShopId, dayStart)
+	if err != nil {
+		return nil, fmt.Errorf("统计今日删除数量失败: %w", err)
+	}
+	if deletedToday+int64(len(candidates)) > int64(g.limits.MaxPerDay) {
+		return nil, fmt.Errorf("%w: 今日已删除 %d 个, 本次 %d 个, 上限 %d",
+			ErrDeleteLimitExceeded, deletedToday, len(candidates), g.limits.MaxPerDay)
+	}
+
+	targets, err := g.loadProducts(ctx, req, candidates)
+	if err != nil {
+		return nil, err
+	}
+	for _, productId := range candidates {
+		if _, ok := targets[productId]; !ok {
+			report.NotFound = append(report.NotFound, productId)
+			continue
+		}
+		report.Planned = append(report.Planned, productId)
+	}
+	if req.DryRun || len(report.Planned) == 0 {
+		return report, nil
+	}
+
+	// 先获取完整信息，删除后可据此重新创建
+	records := make([]model.ProductDeletion, 0, len(report.Planned))
+	for _, productId := range report.Planned {
+		record, err := g.archiveRecord(ctx, req, productId, targets[productId])
+		if err != nil {
+			return nil, fmt.Errorf("获取待删除商品信息失败: %w", err)
+		}
+		payload, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("序列化商品失败, product_id=%d: %w", productId, err)
+		}
+		records = append(records, model.ProductDeletion{
+			RequestID: report.RequestId,
+			ShopID:    req.ShopId,
+			Region:    req.Region,
+			ProductID: productId,
+			Name:      targets[productId].name,
+			Payload:   string(payload),
+			Status:    model.ProductDeletionStatusPending,
+			Operator:  req.Operator,
+		})
+	}
+
+	// 先归档再删除，归档失败时不删除
+	if err := g.store.ArchiveDeletions(records); err != nil {
+		return nil, fmt.Errorf("归档待删除商品失败: %w", err)
+	}
+	for start := 0; start < len(report.Planned); start += g.limits.BatchSize {
+		batch := report.Planned[start:minInt(start+g.limits.BatchSize, len(report.Planned))]
+		if err := ctx.Err(); err != nil {
+			g.markFailed(report, batch, err.Error())
+			continue
+		}
+		failed, err := g.deleteBatch(ctx, req, batch)
+		if err != nil {
+			g.markFailed(report, batch, err.Error())
+			continue
+		}
+		var deleted []int64
+		for _, productId := range batch {
+			if reason, ok := failed[productId]; ok {
+				g.markFailed(report, []int64{productId}, reason)
+				continue
+			}
+			deleted = append(deleted, productId)
+		}
+		report.Deleted = append(report.Deleted, deleted...)
+		if err := g.store.UpdateDeletionStatus(report.RequestId, deleted, model.ProductDeletionStatusDeleted, ""); err != nil {
+			logger.Error("更新删除归档状态失败", zap.String("request_id", report.RequestId), zap.Error(err))
+		}
+	}
+
+	logger.Info("商品删除完成", zap.String("shop_id", req.ShopId), zap.String("request_id", report.RequestId),
+		zap.Int("deleted", len(report.Deleted)), zap.Int("failed", len(report.Failed)),
+		zap.Int("protected", len(report.Protected)))
+	return report, nil
+}
+
+// markFailed 记录删除失败的商品并更新归档状态
+func (g *ProductDeleteGuard) markFailed(report *ProductDeleteReport, productIds []int64, reason string) {
+	for _, productId := range productIds {
+		report.Failed = append(report.Failed, ProductFailure{ItemId: productId, Reason: reason})
+	}
+	if err := g.store.UpdateDeletionStatus(report.RequestId, productIds, model.ProductDeletionStatusFailed, reason); err != nil {
+		logger.Error("更新删除归档状态失败", zap.String("request_id", report.RequestId), zap.Error(err))
+	}
+}
+
+// deleteTarget 店铺中存在的待删除商品，record 为空时归档前再获取完整信息
+type deleteTarget struct {
+	name   string
+	record interface{}
+}
+
+// loadProducts 从在售与已下架列表中查找待删除的商品
+func (g *ProductDeleteGuard) loadProducts(ctx context.Context, req ProductDeleteRequest, productIds []int64) (map[int64]deleteTarget, error) {
+	if req.Auth != nil {
+		return g.loadOpenPlatformProducts(ctx, req, productIds)
+	}
+	wanted := make(map[int64]bool, len(productIds))
+	for _, productId := range productIds {
+		wanted[productId] = true
+	}
+	targets := make(map[int64]deleteTarget, len(productIds))
+	for _, listType := range []string{shopee.ListTypeLive, shopee.ListTypeDelisted} {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		list, err := g.client.GetProductDetailList(req.Cookies, req.ShopId, req.Region, listType)
+		if err != nil {
+			return nil, fmt.Errorf("获取商品列表失败, list_type=%s: %w", listType, err)
+		}
+		for _, product := range list {
+			productId := int64(product.ID)
+			if wanted[productId] {
+				targets[productId] = deleteTarget{name: product.Name}
+			}
+		}
+		if len(targets) == len(wanted) {
+			break
+		}
+	}
+	return targets, nil
+}
+
+// loadOpenPlatformProducts 通过商品基础信息查找待删除的商品，基础信息即为归档内容
+func (g *ProductDeleteGuard) loadOpenPlatformProducts(ctx context.Context, req ProductDeleteRequest, productIds []int64) (map[int64]deleteTarget, error) {
+	items, err := g.client.GetItemBaseInfoListWithAreaTw(ctx, *req.Auth, productIds)
+	if err != nil {
+		return nil, fmt.Errorf("获取商品基础信息失败: %w", err)
+	}
+	existing := make(map[string]bool, len(shopee.TWItemStatusAll))
+	for _, status := range shopee.TWItemStatusAll {
+		existing[status] = true
+	}
+	targets := make(map[int64]deleteTarget, len(items))
+	for _, item := range items {
+		// 已删除的商品仍会返回基础信息
+		if existing[item.ItemStatus] {
+			targets[item.ItemId] = deleteTarget{name: item.ItemName, record: item}
+		}
+	}
+	return targets, nil
+}
+
+// archiveRecord 返回商品归档的完整信息，CNSC 商品归档完整定义，删除后可据此重新创建
+func (g *ProductDeleteGuard) archiveRecord(ctx context.Context, req ProductDeleteRequest, productId int64, target deleteTarget) (interface{}, error) {
+	if target.record != nil {
+		return target.record, nil
+	}
+	return g.client.GetProductDefinition(ctx, req.Cookies, req.ShopId, req.Region, productId)
+}
+
+// deleteBatch 删除一批商品，返回失败的商品及原因
+func (g *ProductDeleteGuard) deleteBatch(ctx context.Context, req ProductDeleteRequest, batch []int64) (map[int64]string, error) {
+	if req.Auth == nil {
+		return g.client.DeleteProductsWithResult(req.ShopId, req.Cookies, req.Region, batch)
+	}
+	// Open Platform 只能逐个删除
+	failed := make(map[int64]string)
+	for _, productId := range batch {
+		if err := ctx.Err(); err != nil {
+			failed[productId] = err.Error()
+			continue
+		}
+		if err := g.client.DeleteItemWithAreaTw(ctx, *req.Auth, productId); err != nil {
+			failed[productId] = err.Error()
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/pool"
+)
+
+type fakeDeletionStore struct {
+	protected    []int64
+	deletedToday int64
+	since        time.Time
+	records      map[int64]model.ProductDeletion
+}
+
+func (s *fakeDeletionStore) ListProtectedProductIds(shopID string) ([]int64, error) {
+	return s.protected, nil
+}
+
+func (s *fakeDeletionStore) CountDeletionsSince(shopID string, since time.Time) (int64, error) {
+	s.since = since
+	return s.deletedToday, nil
+}
+
+func (s *fakeDeletionStore) ArchiveDeletions(records []model.ProductDeletion) error {
+	for _, record := range records {
+		s.records[record.ProductID] = record
+	}
+	return nil
+}
+
+func (s *fakeDeletionStore) UpdateDeletionStatus(requestID string, productIDs []int64, status, reason string) error {
+	for _, productID := range productIDs {
+		record := s.records[productID]
+		record.Status, record.Reason = status, reason
+		s.records[productID] = record
+	}
+	return nil
+}
+
+func TestProductDeleteGuard(t *testing.T) {
+	pool.InitWorkerPool()
+	var deleteCalls [][]int64
+	archivedBeforeDelete := true
+	store := &fakeDeletionStore{protected: []int64{2}, records: make(map[int64]model.ProductDeletion)}
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductList:
+			if r.URL.Query().Get("list_type") == shopee.ListTypeLive {
+				io.WriteString(w, `{"code":0,"data":{"page_info":{"total":2},"products":[{"id":1,"name":"T恤"},{"id":2,"name":"帽子"}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":3,"name":"袜子"}]}}`)
+		case shopee.APIPathGetProductInfo:
+			productId := r.URL.Query().Get("product_id")
+			io.WriteString(w, `{"code":0,"data":{"product_info":{"id":`+productId+`,"name":"T恤","category_path":[100,101]}}}`)
+		case shopee.APIPathDeleteProduct:
+			var req shopee.DeleteProductReq
+			json.NewDecoder(r.Body).Decode(&req)
+			deleteCalls = append(deleteCalls, req.ProductIdList)
+			for _, productId := range req.ProductIdList {
+				if store.records[productId].Status != model.ProductDeletionStatusPending {
+					archivedBeforeDelete = false
+				}
+			}
+			io.WriteString(w, `{"code":0,"data":{"result":[{"id":3,"code":1000601,"user_message":"商品在活动中"}]}}`)
+		}
+	})
+	defer server.Close()
+
+	guard := NewProductDeleteGuard(client, store, ProductDeleteLimits{MaxPerCall: 3, MaxPerDay: 10, BatchSize: 1})
+	req := ProductDeleteRequest{Cookies: "SPC_EC=1;", ShopId: "100", Region: "sg", ProductIds: []int64{1, 2, 3, 4, 1}, DryRun: true}
+	report, err := guard.Delete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Delete(dry run) error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Planned, []int64{1, 3}) || !reflect.DeepEqual(report.Protected, []int64{2}) ||
+		!reflect.DeepEqual(report.NotFound, []int64{4}) {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+	if len(deleteCalls) != 0 || len(store.records) != 0 {
+		t.Fatalf("Dry run should not archive or delete, calls = %v, records = %v", deleteCalls, store.records)
+	}
+
+	req.DryRun = false
+	report, err = guard.Delete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if !reflect.DeepEqual(deleteCalls, [][]int64{{1}, {3}}) || !archivedBeforeDelete {
+		t.Errorf("Unexpected delete calls %v, archived before delete = %v", deleteCalls, archivedBeforeDelete)
+	}
+	if !reflect.DeepEqual(report.Deleted, []int64{1}) || len(report.Failed) != 1 || report.Failed[0].ItemId != 3 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if store.records[1].Status != model.ProductDeletionStatusDeleted || store.records[3].Status != model.ProductDeletionStatusFailed {
+		t.Errorf("Unexpected archive: %+v", store.records)
+	}
+	var archived shopee.ProductDefinition
+	if err := json.Unmarshal([]byte(store.records[1].Payload), &archived); err != nil ||
+		archived.ID != 1 || !reflect.DeepEqual(archived.CategoryPath, []int64{100, 101}) {
+		t.Errorf("Unexpected archived payload %q, error = %v", store.records[1].Payload, err)
+	}
+	if _, offset := store.since.Zone(); offset != 8*3600 || store.since.Hour() != 0 || store.since.Minute() != 0 {
+		t.Errorf("daily count should start from midnight in the shop region, got %v", store.since)
+	}
+
+	req.ProductIds = []int64{1, 3, 5, 6}
+	if _, err := guard.Delete(context.Background(), req); !errors.Is(err, ErrDeleteLimitExceeded) {
+		t.Errorf("Delete() over per call limit error = %v", err)
+	}
+	store.deletedToday = 9
+	req.ProductIds = []int64{1, 3}
+	if _, err := guard.Delete(context.Background(), req); !errors.Is(err, ErrDeleteLimitExceeded) {
+		t.Errorf("Delete() over daily limit error = %v", err)
+	}
+}
+
+func TestProductDeleteWithoutGuard(t *testing.T) {
+	pool.InitWorkerPool()
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductList:
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":1}]}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	rules, err := ParseProductRules([]byte(`[{"name": "all", "action": {"type": "delete"}}]`))
+	if err != nil {
+		t.Fatalf("ParseProductRules() error = %v", err)
+	}
+	reports, err := NewProductRuleEngine(client, rules).Run(context.Background(), "SPC_EC=1;", "100", "sg", RuleModeApply)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(reports) != 1 || reports[0].Error == "" || reports[0].Result != nil {
+		t.Errorf("delete without guard should fail, got %+v", reports)
+	}
+
+	cnsc := &sellerCenterProductService{client: client, cookies: "SPC_EC=1;", shopId: "100", region: "sg"}
+	if _, err := cnsc.DeleteProducts(context.Background(), []int64{1}); err == nil {
+		t.Error("DeleteProducts() without guard should fail")
+	}
+}
+
+func TestSellerCenterProductServiceDeleteProducts(t *testing.T) {
+	store := &fakeDeletionStore{protected: []int64{1}, records: make(map[int64]model.ProductDeletion)}
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("protected products should not be requested, got %s", r.URL.Path)
+	})
+	defer server.Close()
+
+	service, err := NewProductService(client, ShopCredentials{
+		Account:     &model.Account{Cookies: "SPC_EC=1;"},
+		ShopId:      "100",
+		Region:      "sg",
+		DeleteGuard: NewProductDeleteGuard(client, store, ProductDeleteLimits{}),
+	})
+	if err != nil {
+		t.Fatalf("NewProductService() error = %v", err)
+	}
+	result, err := service.DeleteProducts(context.Background(), []int64{1})
+	if err != nil {
+		t.Fatalf("DeleteProducts() error = %v", err)
+	}
+	if len(result.Succeeded) != 0 || len(result.Failed) != 1 || result.Failed[0].ItemId != 1 {
+		t.Errorf("protected product should fail, got %+v", result)
+	}
+}
+
+func TestOpenPlatformProductServiceDeleteProducts(t *testing.T) {
+	var deleted []string
+	store := &fakeDeletionStore{protected: []int64{1}, records: make(map[int64]model.ProductDeletion)}
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathGetBaseProductInfo:
+			if r.URL.Query().Get("item_id_list") != "2,3,4" {
+				t.Errorf("protected products should not be requested, got %s", r.URL.Query().Get("item_id_list"))
+			}
+			io.WriteString(w, `{"response":{"item_list":[{"item_id":2,"item_name":"T恤","item_status":"UNLIST"},{"item_id":4,"item_status":"SELLER_DELETE"}]}}`)
+		case shopee.APIPathDeleteItemForTw:
+			if len(store.records) == 0 {
+				t.Error("products should be archived before deletion")
+			}
+			body, _ := io.ReadAll(r.Body)
+			deleted = append(deleted, string(body))
+			io.WriteString(w, `{"response":{}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	service, err := NewProductService(client, ShopCredentials{
+		ShopeeAccount: &model.ShopeeAccount{ShopId: "100", AccessToken: "token"},
+		DeleteGuard:   NewProductDeleteGuard(client, store, ProductDeleteLimits{}),
+	})
+	if err != nil {
+		t.Fatalf("NewProductService() error = %v", err)
+	}
+	result, err := service.DeleteProducts(context.Background(), []int64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("DeleteProducts() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Succeeded, []int64{2}) || len(result.Failed) != 3 || len(deleted) != 1 {
+		t.Errorf("only item 2 should be deleted, got %+v, delete calls %v", result, deleted)
+	}
+	var archived shopee.ProductBaseInfoWithAreaTw
+	if err := json.Unmarshal([]byte(store.records[2].Payload), &archived); err != nil || archived.ItemName != "T恤" {
+		t.Errorf("Unexpected archived payload %q, error = %v", store.records[2].Payload, err)
+	}
+
+	// 超过单次上限时不删除
+	service, _ = NewProductService(client, ShopCredentials{
+		ShopeeAccount: &model.ShopeeAccount{ShopId: "100", AccessToken: "token"},
+		DeleteGuard:   NewProductDeleteGuard(client, store, ProductDeleteLimits{MaxPerCall: 1}),
+	})
+	if _, err := service.DeleteProducts(context.Background(), []int64{2, 3}); !errors.Is(err, ErrDeleteLimitExceeded) {
+		t.Errorf("DeleteProducts() over limit error = %v", err)
+	}
+}
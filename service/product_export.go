@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/pkg/xlsx"
+)
+
+// 导出格式
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatXLSX  = "xlsx"
+	ExportFormatJSONL = "jsonl"
+)
+
+// ExportTimeLayout CSV 中时间列的格式
+const ExportTimeLayout = "2006-01-02 15:04:05"
+
+// ExportColumnType 导出列的类型，决定各格式中的写法
+type ExportColumnType int
+
+const (
+	ExportColumnString ExportColumnType = iota
+	ExportColumnNumber                  // XLSX 写为数字单元格
+	ExportColumnBool                    // CSV/XLSX 写为 Y/N
+	ExportColumnTime                    // unix 秒，CSV 按 ExportTimeLayout，XLSX 写为日期单元格，JSONL 按 RFC3339
+)
+
+// ExportRecord 导出的一个商品，来源没有提供的字段为零值
+type ExportRecord struct {
+	ProductId  int64
+	Name       string
+	LikedCount int64
+	SoldCount  int64
+	ViewCount  int64
+	CreateTime int64
+	DaysToShip int
+	PreOrder   bool
+	Models     []shopee.Model
+}
+
+// ExportColumn 导出列，Key 用于选择列与 JSONL 字段名，Title 为表头
+type ExportColumn struct {
+	Key   string
+	Title string
+	Type  ExportColumnType
+	Value func(ExportRecord) interface{}
+}
+
+// exportColumns 全部可导出的列
+var exportColumns = []ExportColumn{
+	{"product_id", "商品ID", ExportColumnNumber, func(r ExportRecord) interface{} { return r.ProductId }},
+	{"name", "商品名称", ExportColumnString, func(r ExportRecord) interface{} { return r.Name }},
+	{"liked_count", "点赞数", ExportColumnNumber, func(r ExportRecord) interface{} { return r.LikedCount }},
+	{"sold_count", "销量", ExportColumnNumber, func(r ExportRecord) interface{} { return r.SoldCount }},
+	{"view_count", "浏览量", ExportColumnNumber, func(r ExportRecord) interface{} { return r.ViewCount }},
+	{"create_time", "创建时间", ExportColumnTime, func(r ExportRecord) interface{} { return r.CreateTime }},
+	{"days_to_ship", "出货天数", ExportColumnNumber, func(r ExportRecord) interface{} { return int64(r.DaysToShip) }},
+	{"pre_order", "是否预售", ExportColumnBool, func(r ExportRecord) interface{} { return r.PreOrder }},
+	{"model_count", "规格数", ExportColumnNumber, func(r ExportRecord) interface{} { return int64(len(r.Models)) }},
+	{"model_ids", "规格ID", ExportColumnString, func(r ExportRecord) interface{} {
+		ids := make([]string, 0, len(r.Models))
+		for _, model := range r.Models {
+			ids = append(ids, strconv.Itoa(model.ID))
+		}
+		return strings.Join(ids, ",")
+	}},
+	{"model_names", "规格名称", ExportColumnString, func(r ExportRecord) interface{} {
+		names := make([]string, 0, len(r.Models))
+		for _, model := range r.Models {
+			names = append(names, model.Name)
+		}
+		return strings.Join(names, ",")
+	}},
+}
+
+// DefaultExportColumns 未指定列时导出的列
+var DefaultExportColumns = []string{
+	"product_id", "name", "sold_count", "view_count", "liked_count", "create_time", "days_to_ship", "pre_order", "model_count",
+}
+
+// ExportColumnsByKey 按 key 选择导出列，keys 为空时使用 DefaultExportColumns
+func ExportColumnsByKey(keys ...string) ([]ExportColumn, error) {
+	if len(keys) == 0 {
+		keys = DefaultExportColumns
+	}
+	columns := make([]ExportColumn, 0, len(keys))
+	for _, key := range keys {
+		found := false
+		for _, column := range exportColumns {
+			if column.Key == key {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("未知的导出列: %s", key)
+		}
+	}
+	return columns, nil
+}
+
+// ExportWriter 逐个写入商品，Close 时写入结尾但不关闭底层 writer
+type ExportWriter interface {
+	Write(record ExportRecord) error
+	Close() error
+}
+
+// NewExportWriter 按格式创建导出 writer，表头在创建时写入
+func NewExportWriter(format string, w io.Writer, columns []ExportColumn) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return NewCSVExportWriter(w, columns, true)
+	case ExportFormatXLSX:
+		return NewXLSXExportWriter(w, columns)
+	case ExportFormatJSONL:
+		return NewJSONLExportWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// formatExportText 将列值转换为 CSV/XLSX 中的文本
+func formatExportText(column ExportColumn, value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		if v {
+			return "Y"
+		}
+		return "N"
+	case int64:
+		if column.Type == ExportColumnTime {
+			if v == 0 {
+				return ""
+			}
+			return time.Unix(v, 0).Format(ExportTimeLayout)
+		}
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvExportWriter struct {
+	w       *csv.Writer
+	columns []ExportColumn
+}
+
+// NewCSVExportWriter 创建 CSV writer，bom 为 true 时在文件开头写入一次 UTF-8 BOM，便于 Excel 识别中文
+func NewCSVExportWriter(w io.Writer, columns []ExportColumn, bom bool) (ExportWriter, error) {
+	if bom {
+		if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+			return nil, fmt.Errorf("write bom failed: %w", err)
+		}
+	}
+	cw := &csvExportWriter{w: csv.NewWriter(w), columns: columns}
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Title
+	}
+	if err := cw.w.Write(header); err != nil {
+		return nil, fmt.Errorf("write header failed: %w", err)
+	}
+	return cw, nil
+}
+
+func (cw *csvExportWriter) Write(record ExportRecord) error {
+	row := make([]string, len(cw.columns))
+	for i, column := range cw.columns {
+		row[i] = formatExportText(column, column.Value(record))
+	}
+	return cw.w.Write(row)
+}
+
+func (cw *csvExportWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type xlsxExportWriter struct {
+	sw      *xlsx.StreamWriter
+	columns []ExportColumn
+}
+
+// NewXLSXExportWriter 创建 XLSX writer，数字列写为数字单元格，时间列写为日期单元格
+func NewXLSXExportWriter(w io.Writer, columns []ExportColumn) (ExportWriter, error) {
+	sw, err := xlsx.NewStreamWriter(w, "Products")
+	if err != nil {
+		return nil, err
+	}
+	header := make([]xlsx.Cell, len(columns))
+	for i, column := range columns {
+		header[i] = xlsx.String(column.Title)
+	}
+	if err := sw.WriteRow(header); err != nil {
+		return nil, err
+	}
+	return &xlsxExportWriter{sw: sw, columns: columns}, nil
+}
+
+func (xw *xlsxExportWriter) Write(record ExportRecord) error {
+	cells := make([]xlsx.Cell, len(xw.columns))
+	for i, column := range xw.columns {
+		value := column.Value(record)
+		if number, ok := value.(int64); ok && column.Type == ExportColumnNumber {
+			cells[i] = xlsx.Number(float64(number))
+			continue
+		}
+		if unix, ok := value.(int64); ok && column.Type == ExportColumnTime {
+			if unix != 0 {
+				cells[i] = xlsx.Date(time.Unix(unix, 0))
+			}
+			continue
+		}
+		cells[i] = xlsx.String(formatExportText(column, value))
+	}
+	return xw.sw.WriteRow(cells)
+}
+
+func (xw *xlsxExportWriter) Close() error {
+	return xw.sw.Close()
+}
+
+type jsonlExportWriter struct {
+	enc     *json.Encoder
+	columns []ExportColumn
+}
+
+// NewJSONLExportWriter 创建 JSONL writer，每行一个以列 key 为字段的对象，不写 BOM
+func NewJSONLExportWriter(w io.Writer, columns []ExportColumn) ExportWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlExportWriter{enc: enc, columns: columns}
+}
+
+func (jw *jsonlExportWriter) Write(record ExportRecord) error {
+	line := make(map[string]interface{}, len(jw.columns))
+	for _, column := range jw.columns {
+		value := column.Value(record)
+		if unix, ok := value.(int64); ok && column.Type == ExportColumnTime {
+			if unix == 0 {
+				value = nil
+			} else {
+				value = time.Unix(unix, 0).UTC().Format(time.RFC3339)
+			}
+		}
+		line[column.Key] = value
+	}
+	return jw.enc.Encode(line)
+}
+
+func (jw *jsonlExportWriter) Close() error {
+	return nil
+}
+
+// ProductSource 依次产出导出的商品，emit 返回错误时应停止并返回该错误
+type ProductSource func(ctx context.Context, emit func(ExportRecord) error) error
+
+// CatalogSource 逐页导出 listType 下的全部商品，withDaysToShip 为 true 时合并出货天数与预售信息
+// 出货天数先逐页汇总为商品 id 到出货天数的映射，不保留完整的商品列表
+func CatalogSource(client *shopee.Client, cookies, shopId, region, listType string, withDaysToShip bool) ProductSource {
+	return func(ctx context.Context, emit func(ExportRecord) error) error {
+		type daysToShip struct {
+			days     int
+			preOrder bool
+		}
+		details := make(map[int64]daysToShip)
+		if withDaysToShip {
+			err := client.RangeProductDetails(ctx, cookies, shopId, region, listType, func(page []shopee.ProductDetail) bool {
+				for _, detail := range page {
+					details[int64(detail.ID)] = daysToShip{detail.DaysToShip, detail.PreOrder}
+				}
+				return true
+			})
+			if err != nil {
+				return fmt.Errorf("获取商品出货天数失败: %w", err)
+			}
+		}
+
+		var emitErr error
+		err := client.SearchProducts(ctx, cookies, shopId, region, shopee.ProductListRequest{ListType: listType},
+			func(product shopee.Product) bool {
+				record := recordFromProduct(product)
+				if detail, ok := details[record.ProductId]; ok {
+					record.DaysToShip = detail.days
+					record.PreOrder = detail.preOrder
+				}
+				emitErr = emit(record)
+				return emitErr == nil
+			})
+		if emitErr != nil {
+			return emitErr
+		}
+		if err != nil {
+			return fmt.Errorf("获取商品列表失败: %w", err)
+		}
+		return nil
+	}
+}
+
+// DaysToShipSource 逐页导出出货天数不等于 dayToShip 的商品，包含 id、名称、创建时间、出货天数与规格
+func DaysToShipSource(client *shopee.Client, cookies, shopId, region, listType string, dayToShip int) ProductSource {
+	return func(ctx context.Context, emit func(ExportRecord) error) error {
+		var emitErr error
+		err := client.RangeProductDetails(ctx, cookies, shopId, region, listType, func(page []shopee.ProductDetail) bool {
+			for _, detail := range page {
+				if detail.DaysToShip == dayToShip {
+					continue
+				}
+				emitErr = emit(ExportRecord{
+					ProductId:  int64(detail.ID),
+					Name:       detail.Name,
+					CreateTime: detail.CreateTime,
+					DaysToShip: detail.DaysToShip,
+					PreOrder:   detail.PreOrder,
+					Models:     detail.ModelList,
+				})
+				if emitErr != nil {
+					return false
+				}
+			}
+			return true
+		})
+		if emitErr != nil {
+			return emitErr
+		}
+		if err != nil {
+			return fmt.Errorf("获取商品列表失败: %w", err)
+		}
+		return nil
+	}
+}
+
+// SearchSource 使用商品搜索逐页导出，不会一次性加载全部商品
+func SearchSource(client *shopee.Client, cookies, shopId, region string, req shopee.ProductListRequest) ProductSource {
+	return func(ctx context.Context, emit func(ExportRecord) error) error {
+		var emitErr error
+		err := client.SearchProducts(ctx, cookies, shopId, region, req, func(product shopee.Product) bool {
+			emitErr = emit(recordFromProduct(product))
+			return emitErr == nil
+		})
+		if emitErr != nil {
+			return emitErr
+		}
+		return err
+	}
+}
+
+func recordFromProduct(product shopee.Product) ExportRecord {
+	return ExportRecord{
+		ProductId:  int64(product.ID),
+		Name:       product.Name,
+		LikedCount: product.Statistics.LikedCount,
+		SoldCount:  product.Statistics.SoldCount,
+		ViewCount:  product.Statistics.ViewCount,
+		CreateTime: product.CreateTime,
+		Models:     product.ModelList,
+	}
+}
+
+// ExportProducts 将 source 中的商品逐个写入 writer 并关闭 writer，返回写入的商品数
+func ExportProducts(ctx context.Context, source ProductSource, writer ExportWriter) (int, error) {
+	count := 0
+	err := source(ctx, func(record ExportRecord) error {
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入商品 %d 失败: %w", record.ProductId, err)
+		}
+		count++
+		return nil
+	})
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
+
+// ExportProductsToFile 导出到文件，format 为空时按扩展名判断；先写临时文件，成功后再重命名，失败时不留下残缺文件
+func ExportProductsToFile(ctx context.Context, source ProductSource, path, format string, columnKeys ...string) (int, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	columns, err := ExportColumnsByKey(columnKeys...)
+	if err != nil {
+		return 0, err
+	}
+
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, fmt.Errorf("创建导出文件失败: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	writer, err := NewExportWriter(format, file, columns)
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+	count, err := ExportProducts(ctx, source, writer)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return count, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return count, fmt.Errorf("保存导出文件失败: %w", err)
+	}
+	logger.Info("商品导出完成", zap.String("path", path), zap.String("format", format), zap.Int("count", count))
+	return count, nil
+}
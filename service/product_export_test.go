@@ -0,0 +1,148 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/xlsx"
+)
+
+func TestExportProductsToFile(t *testing.T) {
+	createTime := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC).Unix()
+	records := []ExportRecord{
+		{ProductId: 1, Name: "T恤, 白色", SoldCount: 3, CreateTime: createTime, DaysToShip: 7, PreOrder: true,
+			Models: []shopee.Model{{ID: 11, Name: "S"}, {ID: 12, Name: "M"}}},
+		{ProductId: 2, Name: "帽子"},
+	}
+	source := func(ctx context.Context, emit func(ExportRecord) error) error {
+		for _, record := range records {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	csvPath := filepath.Join(dir, "products.csv")
+	if _, err := ExportProductsToFile(ctx, source, csvPath, "", "product_id", "name", "pre_order", "model_ids"); err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	data, _ := os.ReadFile(csvPath)
+	if !bytes.HasPrefix(data, []byte("\xEF\xBB\xBF商品ID,")) || bytes.Count(data, []byte("\xEF\xBB\xBF")) != 1 {
+		t.Errorf("csv should start with exactly one BOM, got %q", data)
+	}
+	if !strings.Contains(string(data), `1,"T恤, 白色",Y,"11,12"`) {
+		t.Errorf("unexpected csv content %q", data)
+	}
+
+	xlsxPath := filepath.Join(dir, "products.xlsx")
+	count, err := ExportProductsToFile(ctx, source, xlsxPath, "", "product_id", "sold_count", "create_time")
+	if err != nil || count != 2 {
+		t.Fatalf("export xlsx: count=%d, err=%v", count, err)
+	}
+	data, _ = os.ReadFile(xlsxPath)
+	rows, err := xlsx.ReadBytes(data)
+	if err != nil {
+		t.Fatalf("read xlsx: %v", err)
+	}
+	want := []string{"1", "3", xlsx.Date(time.Unix(createTime, 0)).Value}
+	if len(rows) != 3 || !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("unexpected xlsx rows %q", rows)
+	}
+	zr, _ := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	for _, entry := range zr.File {
+		if entry.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, _ := entry.Open()
+		sheet, _ := io.ReadAll(rc)
+		rc.Close()
+		if !bytes.Contains(sheet, []byte(`<c r="B2"><v>3</v></c>`)) {
+			t.Errorf("sold_count should be a numeric cell, got %s", sheet)
+		}
+		if !bytes.Contains(sheet, []byte(`<c r="C2" s="1">`)) || bytes.Contains(sheet, []byte(`r="C3"`)) {
+			t.Errorf("create_time should be a date cell and empty when unknown, got %s", sheet)
+		}
+	}
+
+	jsonlPath := filepath.Join(dir, "products.jsonl")
+	if _, err := ExportProductsToFile(ctx, source, jsonlPath, "", "product_id", "pre_order", "create_time"); err != nil {
+		t.Fatalf("export jsonl: %v", err)
+	}
+	file, _ := os.Open(jsonlPath)
+	defer file.Close()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid jsonl line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || lines[0]["pre_order"] != true || lines[0]["create_time"] != "2024-05-01T08:00:00Z" || lines[1]["create_time"] != nil {
+		t.Errorf("unexpected jsonl lines %v", lines)
+	}
+
+	if _, err := ExportProductsToFile(ctx, source, filepath.Join(dir, "bad.csv"), "", "unknown"); err == nil {
+		t.Error("unknown column should fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bad.csv")); !os.IsNotExist(err) {
+		t.Error("failed export should not leave a file")
+	}
+}
+
+func TestCatalogSources(t *testing.T) {
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathSearchProductList:
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":3},"products":[{"id":1,"statistics":{"sold_count":3}},{"id":2},{"id":3}]}}`)
+		case shopee.APIPathProductDetailList:
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":2},"list":[{"id":1,"days_to_ship":7,"pre_order":true},{"id":2,"days_to_ship":2}]}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	collect := func(source ProductSource) []ExportRecord {
+		var records []ExportRecord
+		if err := source(context.Background(), func(record ExportRecord) error {
+			records = append(records, record)
+			return nil
+		}); err != nil {
+			t.Fatalf("source error = %v", err)
+		}
+		return records
+	}
+
+	records := collect(CatalogSource(client, "SPC_EC=1;", "100", "sg", shopee.ListTypeLive, true))
+	if len(records) != 3 || records[0].SoldCount != 3 || records[0].DaysToShip != 7 || !records[0].PreOrder ||
+		records[1].DaysToShip != 2 || records[2].DaysToShip != 0 {
+		t.Errorf("Unexpected catalog records %+v", records)
+	}
+
+	records = collect(DaysToShipSource(client, "SPC_EC=1;", "100", "sg", shopee.ListTypeLive, 2))
+	if len(records) == 0 || records[0].ProductId != 1 || records[0].DaysToShip != 7 {
+		t.Errorf("Unexpected days to ship records %+v", records)
+	}
+	for _, record := range records {
+		if record.ProductId == 2 {
+			t.Errorf("product with 2 days to ship should be skipped, got %+v", records)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+)
+
+// 规则可用的商品字段
+const (
+	RuleFieldLikedCount = "liked_count"
+	RuleFieldSoldCount  = "sold_count"
+	RuleFieldViewCount  = "view_count"
+	RuleFieldAgeDays    = "age_days"    // 上架天数
+	RuleFieldCreateTime = "create_time" // 创建时间(秒)
+	RuleFieldStatus     = "status"      // live / unlisted
+	RuleFieldDaysToShip = "days_to_ship"
+	RuleFieldPreOrder   = "pre_order"
+	RuleFieldInCampaign = "in_campaign" // 是否参与进行中的活动
+)
+
+// 条件运算符
+const (
+	RuleOpEq  = "eq"
+	RuleOpNe  = "ne"
+	RuleOpGt  = "gt"
+	RuleOpGte = "gte"
+	RuleOpLt  = "lt"
+	RuleOpLte = "lte"
+)
+
+// 规则动作
+const (
+	RuleActionUnlist        = "unlist"
+	RuleActionDelete        = "delete"
+	RuleActionSetDaysToShip = "set_days_to_ship"
+	RuleActionAddToDiscount = "add_to_discount"
+)
+
+// 商品状态
+const (
+	RuleStatusLive     = "live"
+	RuleStatusUnlisted = "unlisted"
+)
+
+// RuleMode 规则执行模式
+type RuleMode string
+
+const (
+	RuleModeDryRun RuleMode = "dry_run" // 只计算命中的商品
+	RuleModeApply  RuleMode = "apply"   // 执行动作
+)
+
+// RuleCondition 单个条件，Value 为数字、布尔或字符串
+type RuleCondition struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+// RuleAction 规则命中后执行的动作
+type RuleAction struct {
+	Type           string `json:"type"`
+	DaysToShip     int    `json:"days_to_ship,omitempty"`    // set_days_to_ship
+	DiscountId     int64  `json:"discount_id,omitempty"`     // add_to_discount
+	PromotionPrice int64  `json:"promotion_price,omitempty"` // add_to_discount，活动价(分)，商品下所有规格相同
+	PromotionStock int    `json:"promotion_stock,omitempty"` // add_to_discount
+	UserItemLimit  int    `json:"user_item_limit,omitempty"` // add_to_discount
+}
+
+// ProductRule 商品生命周期规则，所有条件都满足才命中
+type ProductRule struct {
+	Name       string          `json:"name"`
+	Disabled   bool            `json:"disabled,omitempty"`
+	Conditions []RuleCondition `json:"conditions"`
+	SortBy     string          `json:"sort_by,omitempty"` // 为空时按命中顺序
+	Desc       bool            `json:"desc,omitempty"`
+	Limit      int             `json:"limit,omitempty"` // 0 表示不限制
+	Action     RuleAction      `json:"action"`
+}
+
+// ProductFacts 规则判断使用的商品信息
+type ProductFacts struct {
+	ItemId     int64
+	Name       string
+	LikedCount int64
+	SoldCount  int64
+	ViewCount  int64
+	CreateTime int64
+	Status     string
+	DaysToShip int
+	PreOrder   bool
+	InCampaign bool
+	ModelIds   []int64
+}
+
+// RuleReport 单条规则的执行结果
+type RuleReport struct {
+	Rule    string                  `json:"rule"`
+	Action  string                  `json:"action"`
+	Mode    RuleMode                `json:"mode"`
+	Matched []int64                 `json:"matched"`
+	Result  *ProductOperationResult `json:"result,omitempty"` // apply 模式下的执行结果
+	Error   string                  `json:"error,omitempty"`
+}
+
+// ParseProductRules 解析 json 格式的规则配置并校验
+func ParseProductRules(data []byte) ([]ProductRule, error) {
+	var rules []ProductRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析规则配置失败: %w", err)
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// LoadProductRules 从 json 文件加载规则
+func LoadProductRules(path string) ([]ProductRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取规则配置失败: %w", err)
+	}
+	return ParseProductRules(data)
+}
+
+// Validate 校验规则的字段、运算符与动作参数
+func (r ProductRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("规则名称不能为空")
+	}
+	for _, condition := range r.Conditions {
+		if _, err := (ProductFacts{}).value(condition.Field, time.Now()); err != nil {
+			return fmt.Errorf("规则 %s: %w", r.Name, err)
+		}
+		switch condition.Op {
+		case RuleOpEq, RuleOpNe, RuleOpGt, RuleOpGte, RuleOpLt, RuleOpLte:
+		default:
+			return fmt.Errorf("规则 %s: 未知的运算符 %s", r.Name, condition.Op)
+		}
+	}
+	if r.SortBy != "" {
+		if _, err := (ProductFacts{}).value(r.SortBy, time.Now()); err != nil {
+			return fmt.Errorf("规则 %s: 排序%w", r.Name, err)
+		}
+	}
+	switch r.Action.Type {
+	case RuleActionUnlist, RuleActionDelete:
+	case RuleActionSetDaysToShip:
+		if r.Action.DaysToShip <= 0 {
+			return fmt.Errorf("规则 %s: 需要指定 days_to_ship", r.Name)
+		}
+	case RuleActionAddToDiscount:
+		if r.Action.DiscountId == 0 || r.Action.PromotionPrice <= 0 {
+			return fmt.Errorf("规则 %s: 需要指定 discount_id 与 promotion_price", r.Name)
+		}
+	default:
+		return fmt.Errorf("规则 %s: 未知的动作 %s", r.Name, r.Action.Type)
+	}
+	return nil
+}
+
+// Match 返回命中规则的商品，已按 SortBy 排序并截取 Limit 个
+func (r ProductRule) Match(products []ProductFacts, now time.Time) []ProductFacts {
+	var matched []ProductFacts
+	for _, product := range products {
+		if r.matchOne(product, now) {
+			matched = append(matched, product)
+		}
+	}
+	if r.SortBy != "" {
+		sort.SliceStable(matched, func(i, j int) bool {
+			a, _ := matched[i].value(r.SortBy, now)
+			b, _ := matched[j].value(r.SortBy, now)
+			if r.Desc {
+				return compareRuleValue(a, b) > 0
+			}
+			return compareRuleValue(a, b) < 0
+		})
+	}
+	if r.Limit > 0 && len(matched) > r.Limit {
+		matched = matched[:r.Limit]
+	}
+	return matched
+}
+
+func (r ProductRule) matchOne(product ProductFacts, now time.Time) bool {
+	for _, condition := range r.Conditions {
+		actual, err := product.value(condition.Field, now)
+		if err != nil {
+			return false
+		}
+		expected := normalizeRuleValue(condition.Value)
+		if !sameRuleValueKind(actual, expected) {
+			return false
+		}
+		cmp := compareRuleValue(actual, expected)
+		var ok bool
+		switch condition.Op {
+		case RuleOpEq:
+			ok = cmp == 0
+		case RuleOpNe:
+			ok = cmp != 0
+		case RuleOpGt:
+			ok = cmp > 0
+		case RuleOpGte:
+			ok = cmp >= 0
+		case RuleOpLt:
+			ok = cmp < 0
+		case RuleOpLte:
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// value 返回字段值，数字统一为 float64
+func (p ProductFacts) value(field string, now time.Time) (interface{}, error) {
+	switch field {
+	case RuleFieldLikedCount:
+		return float64(p.LikedCount), nil
+	case RuleFieldSoldCount:
+		return float64(p.SoldCount), nil
+	case RuleFieldViewCount:
+		return float64(p.ViewCount), nil
+	case RuleFieldAgeDays:
+		return now.Sub(time.Unix(p.CreateTime, 0)).Hours() / 24, nil
+	case RuleFieldCreateTime:
+		return float64(p.CreateTime), nil
+	case RuleFieldStatus:
+		return p.Status, nil
+	case RuleFieldDaysToShip:
+		return float64(p.DaysToShip), nil
+	case RuleFieldPreOrder:
+		return p.PreOrder, nil
+	case RuleFieldInCampaign:
+		return p.InCampaign, nil
+	}
+	return nil, fmt.Errorf("未知的字段 %s", field)
+}
+
+func normalizeRuleValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return value
+}
+
+func sameRuleValueKind(a, b interface{}) bool {
+	switch a.(type) {
+	case float64:
+		_, ok := b.(float64)
+		return ok
+	case string:
+		_, ok := b.(string)
+		return ok
+	case bool:
+		_, ok := b.(bool)
+		return ok
+	}
+	return false
+}
+
+// compareRuleValue 比较同类型的值，false < true
+func compareRuleValue(a, b interface{}) int {
+	switch av := a.(type) {
+	case float64:
+		bv := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	case string:
+		bv := b.(string)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	case bool:
+		bv := b.(bool)
+		if av != bv {
+			if bv {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// ProductRuleEngine 按店铺执行商品生命周期规则
+type ProductRuleEngine struct {
+	client      *shopee.Client
+	rules       []ProductRule
+	deleteGuard *ProductDeleteGuard
+}
+
+// RuleEngineOption 规则引擎选项
+type RuleEngineOption func(*ProductRuleEngine)
+
+// WithRuleDeleteGuard 删除动作经过删除保护执行，未配置时删除动作返回错误
+func WithRuleDeleteGuard(guard *ProductDeleteGuard) RuleEngineOption {
+	return func(e *ProductRuleEngine) {
+		e.deleteGuard = guard
+	}
+}
+
+// NewProductRuleEngine 创建规则引擎
+func NewProductRuleEngine(client *shopee.Client, rules []ProductRule, opts ...RuleEngineOption) *ProductRuleEngine {
+	e := &ProductRuleEngine{client: client, rules: rules}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Run 加载店铺商品并依次执行规则，已被前面规则命中的商品不会再参与后续规则
+func (e *ProductRuleEngine) Run(ctx context.Context, cookies, shopId, region string, mode RuleMode) ([]RuleReport, error) {
+	products, err := e.loadProductFacts(cookies, shopId, region, e.needDaysToShip())
+	if err != nil {
+		return nil, err
+	}
+	cnsc := &sellerCenterProductService{client: e.client, cookies: cookies, shopId: shopId, region: region, deleteGuard: e.deleteGuard}
+
+	now := time.Now()
+	handled := make(map[int64]bool)
+	var reports []RuleReport
+	for _, rule := range e.rules {
+		if rule.Disabled {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return reports, err
+		}
+
+		candidates := make([]ProductFacts, 0, len(products))
+		for _, product := range products {
+			if !handled[product.ItemId] {
+				candidates = append(candidates, product)
+			}
+		}
+		matched := rule.Match(candidates, now)
+
+		report := RuleReport{Rule: rule.Name, Action: rule.Action.Type, Mode: mode}
+		for _, product := range matched {
+			report.Matched = append(report.Matched, product.ItemId)
+			handled[product.ItemId] = true
+		}
+		if mode == RuleModeApply && len(matched) > 0 {
+			report.Result, err = e.apply(ctx, cnsc, rule.Action, matched)
+			if err != nil {
+				report.Error = err.Error()
+			}
+		}
+		reports = append(reports, report)
+
+		logger.Info("规则执行完成", zap.String("shop_id", shopId), zap.String("rule", rule.Name),
+			zap.String("mode", string(mode)), zap.Int("matched", len(report.Matched)))
+	}
+	return reports, nil
+}
+
+// needDaysToShip 启用的规则是否用到出货天数或预售字段，不需要时不下载带出货天数的商品列表
+func (e *ProductRuleEngine) needDaysToShip() bool {
+	for _, rule := range e.rules {
+		if rule.Disabled {
+			continue
+		}
+		fields := []string{rule.SortBy}
+		for _, condition := range rule.Conditions {
+			fields = append(fields, condition.Field)
+		}
+		for _, field := range fields {
+			if field == RuleFieldDaysToShip || field == RuleFieldPreOrder {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadProductFacts 汇总在售与已下架商品的统计数据、出货天数与活动信息
+func (e *ProductRuleEngine) LoadProductFacts(cookies, shopId, region string) ([]ProductFacts, error) {
+	return e.loadProductFacts(cookies, shopId, region, true)
+}
+
+func (e *ProductRuleEngine) loadProductFacts(cookies, shopId, region string, withDaysToShip bool) ([]ProductFacts, error) {
+	var products []ProductFacts
+	for _, list := range []struct{ listType, status string }{
+		{shopee.ListTypeLive, RuleStatusLive},
+		{shopee.ListTypeDelisted, RuleStatusUnlisted},
+	} {
+		productList, err := e.client.GetProductDetailList(cookies, shopId, region, list.listType)
+		if err != nil {
+			return nil, fmt.Errorf("获取商品列表失败, list_type=%s: %w", list.listType, err)
+		}
+		detailMap := make(map[int]shopee.ProductDetail)
+		if withDaysToShip {
+			details, err := e.client.GetProductDetailListWithDayToShip(cookies, shopId, region, list.listType)
+			if err != nil {
+				return nil, fmt.Errorf("获取商品出货天数失败, list_type=%s: %w", list.listType, err)
+			}
+			for _, detail := range details {
+				detailMap[detail.ID] = detail
+			}
+		}
+
+		for _, product := range productList {
+			facts := ProductFacts{
+				ItemId:     int64(product.ID),
+				Name:       product.Name,
+				LikedCount: product.Statistics.LikedCount,
+				SoldCount:  product.Statistics.SoldCount,
+				ViewCount:  product.Statistics.ViewCount,
+				CreateTime: product.CreateTime,
+				Status:     list.status,
+				InCampaign: len(product.PromotionDetail.OngoingCampaigns) > 0,
+			}
+			if detail, ok := detailMap[product.ID]; ok {
+				facts.DaysToShip = detail.DaysToShip
+				facts.PreOrder = detail.PreOrder
+			}
+			for _, m := range product.ModelList {
+				facts.ModelIds = append(facts.ModelIds, int64(m.ID))
+			}
+			products = append(products, facts)
+		}
+	}
+	return products, nil
+}
+
+// apply 执行规则动作
+func (e *ProductRuleEngine) apply(ctx context.Context, cnsc *sellerCenterProductService, action RuleAction, products []ProductFacts) (*ProductOperationResult, error) {
+	itemIdList := make([]int64, 0, len(products))
+	for _, product := range products {
+		itemIdList = append(itemIdList, product.ItemId)
+	}
+
+	switch action.Type {
+	case RuleActionUnlist:
+		return cnsc.SetListed(ctx, itemIdList, false)
+	case RuleActionDelete:
+		if e.deleteGuard == nil {
+			return nil, fmt.Errorf("删除动作需要配置删除保护")
+		}
+		return cnsc.deleteProducts(ctx, itemIdList, "rule_engine")
+	case RuleActionSetDaysToShip:
+		// 按商品当前的上下架状态提交，避免批量接口上架已下架的商品
+		unlisted := make(map[int64]bool, len(products))
+		for _, product := range products {
+			unlisted[product.ItemId] = product.Status == RuleStatusUnlisted
+		}
+		return cnsc.setDaysToShip(ctx, itemIdList, action.DaysToShip, unlisted)
+	case RuleActionAddToDiscount:
+		return e.addToDiscount(cnsc, action, products)
+	}
+	return nil, fmt.Errorf("未知的动作 %s", action.Type)
+}
+
+// addToDiscount 将商品的全部规格加入折扣活动
+func (e *ProductRuleEngine) addToDiscount(cnsc *sellerCenterProductService, action RuleAction, products []ProductFacts) (*ProductOperationResult, error) {
+	result := &ProductOperationResult{}
+	for _, product := range products {
+		modelIds := product.ModelIds
+		if len(modelIds) == 0 {
+			modelIds = []int64{0}
+		}
+		req := shopee.UpdateDiscountItemRequest{PromotionId: int(action.DiscountId)}
+		for _, modelId := range modelIds {
+			req.DiscountModelList = append(req.DiscountModelList, shopee.DiscountItemList{
+				ItemID:         product.ItemId,
+				ModelID:        modelId,
+				PromotionPrice: action.PromotionPrice,
+				PromotionStock: action.PromotionStock,
+				UserItemLimit:  action.UserItemLimit,
+				Status:         1,
+			})
+		}
+		if _, err := e.client.UpdateDiscountItem(cnsc.cookies, cnsc.shopId, cnsc.region, req); err != nil {
+			result.Failed = append(result.Failed, ProductFailure{ItemId: product.ItemId, Reason: err.Error()})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, product.ItemId)
+	}
+	return result, nil
+}
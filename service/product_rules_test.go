@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/pool"
+)
+
+func TestProductRuleMatch(t *testing.T) {
+	rules, err := ParseProductRules([]byte(`[
+		{
+			"name": "inactive",
+			"conditions": [
+				{"field": "liked_count", "op": "eq", "value": 0},
+				{"field": "sold_count", "op": "eq", "value": 0},
+				{"field": "status", "op": "eq", "value": "live"},
+				{"field": "in_campaign", "op": "eq", "value": false},
+				{"field": "age_days", "op": "gte", "value": 30}
+			],
+			"sort_by": "create_time",
+			"limit": 2,
+			"action": {"type": "unlist"}
+		}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseProductRules() error = %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	old := now.Add(-60 * 24 * time.Hour).Unix()
+	products := []ProductFacts{
+		{ItemId: 1, Status: RuleStatusLive, CreateTime: old + 2},
+		{ItemId: 2, Status: RuleStatusLive, CreateTime: old},
+		{ItemId: 3, Status: RuleStatusLive, CreateTime: old + 1},
+		{ItemId: 4, Status: RuleStatusLive, CreateTime: old, SoldCount: 1},
+		{ItemId: 5, Status: RuleStatusLive, CreateTime: old, InCampaign: true},
+		{ItemId: 6, Status: RuleStatusUnlisted, CreateTime: old},
+		{ItemId: 7, Status: RuleStatusLive, CreateTime: now.Unix()},
+	}
+	matched := rules[0].Match(products, now)
+	if len(matched) != 2 || matched[0].ItemId != 2 || matched[1].ItemId != 3 {
+		t.Errorf("Expected oldest inactive items [2 3], got %+v", matched)
+	}
+}
+
+func TestParseProductRulesValidation(t *testing.T) {
+	invalid := []string{
+		`[{"name":"a","conditions":[{"field":"unknown","op":"eq","value":1}],"action":{"type":"unlist"}}]`,
+		`[{"name":"a","conditions":[{"field":"sold_count","op":"like","value":1}],"action":{"type":"unlist"}}]`,
+		`[{"name":"a","action":{"type":"set_days_to_ship"}}]`,
+		`[{"name":"a","action":{"type":"add_to_discount","discount_id":1}}]`,
+		`[{"name":"a","action":{"type":"archive"}}]`,
+	}
+	for _, config := range invalid {
+		if _, err := ParseProductRules([]byte(config)); err == nil {
+			t.Errorf("Expected validation error for %s", config)
+		}
+	}
+}
+
+func TestProductRuleEngineRun(t *testing.T) {
+	pool.InitWorkerPool()
+
+	var detailCalls int32
+	var sent []shopee.BatchUpdateProductInfoItem
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductList:
+			if r.URL.Query().Get("list_type") == shopee.ListTypeLive {
+				io.WriteString(w, `{"code":0,"data":{"page_info":{"total":2},"products":[{"id":1,"statistics":{"sold_count":0}},{"id":2,"statistics":{"sold_count":5}}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":3,"statistics":{"sold_count":0}}]}}`)
+		case shopee.APIPathProductDetailList:
+			atomic.AddInt32(&detailCalls, 1)
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":0},"list":[]}}`)
+		case shopee.APIPathBatchUpdateProductInfo:
+			json.NewDecoder(r.Body).Decode(&sent)
+			io.WriteString(w, `{"code":0,"data":{"result":[]}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	rules, err := ParseProductRules([]byte(`[
+		{"name": "no_sales", "conditions": [{"field": "sold_count", "op": "eq", "value": 0}], "action": {"type": "set_days_to_ship", "days_to_ship": 7}}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseProductRules() error = %v", err)
+	}
+	engine := NewProductRuleEngine(client, rules)
+
+	reports, err := engine.Run(context.Background(), "SPC_EC=1;", "100", "sg", RuleModeDryRun)
+	if err != nil {
+		t.Fatalf("Run(dry_run) error = %v", err)
+	}
+	if len(reports) != 1 || len(reports[0].Matched) != 2 || reports[0].Result != nil || sent != nil {
+		t.Fatalf("Unexpected dry run reports: %+v, sent = %+v", reports, sent)
+	}
+	if atomic.LoadInt32(&detailCalls) != 0 {
+		t.Errorf("rules without days_to_ship should not load the days to ship list")
+	}
+
+	reports, err = engine.Run(context.Background(), "SPC_EC=1;", "100", "sg", RuleModeApply)
+	if err != nil {
+		t.Fatalf("Run(apply) error = %v", err)
+	}
+	if len(reports) != 1 || reports[0].Error != "" || reports[0].Result == nil || len(reports[0].Result.Succeeded) != 2 {
+		t.Fatalf("Unexpected apply reports: %+v", reports)
+	}
+	want := []shopee.BatchUpdateProductInfoItem{
+		{ID: 1, DaysToShip: 7, PreOrder: true},
+		{ID: 3, DaysToShip: 7, PreOrder: true, Unlisted: true},
+	}
+	if len(sent) == 2 && sent[0].ID == 3 {
+		sent[0], sent[1] = sent[1], sent[0]
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %+v, want %+v", sent, want)
+	}
+}
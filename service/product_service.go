@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+)
+
+// 商品服务后端
+const (
+	ProductBackendSellerCenter = "seller_center" // cookie 登录的 CNSC 店铺
+	ProductBackendOpenPlatform = "open_platform" // token 授权的 Open Platform 店铺
+)
+
+// cnscBatchSize CNSC 批量更新单次提交的商品数
+const cnscBatchSize = 50
+
+// openPlatformRegion Open Platform 店铺所在区域
+const openPlatformRegion = "tw"
+
+// ProductInfo 统一的商品信息，Status 使用 Open Platform 的商品状态
+type ProductInfo struct {
+	ItemId     int64  `json:"item_id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	IsPreOrder bool   `json:"is_pre_order"`
+	DaysToShip int    `json:"days_to_ship"`
+	CreateTime int64  `json:"create_time"`
+}
+
+// ProductFailure 操作失败的商品
+type ProductFailure struct {
+	ItemId int64  `json:"item_id"`
+	Reason string `json:"reason"`
+}
+
+// ProductOperationResult 批量操作结果
+type ProductOperationResult struct {
+	Succeeded []int64          `json:"succeeded"`
+	Failed    []ProductFailure `json:"failed"`
+}
+
+func (r *ProductOperationResult) fail(itemIdList []int64, reason string) {
+	for _, itemId := range itemIdList {
+		r.Failed = append(r.Failed, ProductFailure{ItemId: itemId, Reason: reason})
+	}
+}
+
+// ProductService 屏蔽 CNSC 与 Open Platform 差异的商品操作
+type ProductService interface {
+	// Backend 返回实现所使用的后端
+	Backend() string
+	// ListProducts 获取店铺在售与已下架的商品 id
+	ListProducts(ctx context.Context) ([]int64, error)
+	// GetProducts 获取商品信息，不存在的商品不会出现在结果中
+	GetProducts(ctx context.Context, itemIdList []int64) ([]ProductInfo, error)
+	// SetDaysToShip 设置出货天数，2 天表示非预售
+	SetDaysToShip(ctx context.Context, itemIdList []int64, daysToShip int) (*ProductOperationResult, error)
+	// SetListed 上架(listed=true)或下架商品
+	SetListed(ctx context.Context, itemIdList []int64, listed bool) (*ProductOperationResult, error)
+	// DeleteProducts 经过删除保护删除商品
+	DeleteProducts(ctx context.Context, itemIdList []int64) (*ProductOperationResult, error)
+}
+
+// ShopCredentials 店铺凭证，ShopeeAccount 有 token 时使用 Open Platform，否则使用 Account 的 cookies
+type ShopCredentials struct {
+	Account       *model.Account       // CNSC 登录账号
+	ShopeeAccount *model.ShopeeAccount // Open Platform 授权店铺
+	ShopId        string               // CNSC 店铺 id
+	Region        string               // CNSC 店铺区域
+	Tokens        TokenSource          // 为空时直接使用 ShopeeAccount 中的 access_token
+	DeleteGuard   *ProductDeleteGuard  // 删除商品使用的删除保护，为空时使用默认限制与数据库存储
+}
+
+// NewProductService 根据店铺凭证选择商品服务实现
+func NewProductService(client *shopee.Client, creds ShopCredentials) (ProductService, error) {
+	deleteGuard := creds.DeleteGuard
+	if deleteGuard == nil {
+		deleteGuard = NewProductDeleteGuard(client, nil, ProductDeleteLimits{})
+	}
+	if account := creds.ShopeeAccount; account != nil && account.ShopId != "" {
+		if account.IsInactive() {
+			return nil, fmt.Errorf("店铺 %s 授权已失效", account.ShopId)
+		}
+		tokens := creds.Tokens
+		if tokens == nil {
+			if account.AccessToken == "" {
+				return nil, fmt.Errorf("店铺 %s 未授权", account.ShopId)
+			}
+			tokens = staticTokenSource(account.AccessToken)
+		}
+		return &openPlatformProductService{client: client, tokens: tokens, shopId: account.ShopId, deleteGuard: deleteGuard}, nil
+	}
+
+	if account := creds.Account; account != nil && account.Cookies != "" {
+		if creds.ShopId == "" || creds.Region == "" {
+			return nil, fmt.Errorf("cookie 账号需要指定店铺 id 与区域")
+		}
+		return &sellerCenterProductService{
+			client:      client,
+			cookies:     account.Cookies,
+			shopId:      creds.ShopId,
+			region:      creds.Region,
+			deleteGuard: deleteGuard,
+		}, nil
+	}
+	return nil, fmt.Errorf("缺少可用的店铺凭证")
+}
+
+// staticTokenSource 固定的 access_token
+type staticTokenSource string
+
+func (s staticTokenSource) Token(ctx context.Context, shopId string) (string, error) {
+	return string(s), nil
+}
+
+// sellerCenterProductService 基于 cookie 的 CNSC 实现
+type sellerCenterProductService struct {
+	client      *shopee.Client
+	cookies     string
+	shopId      string
+	region      string
+	deleteGuard *ProductDeleteGuard
+}
+
+func (s *sellerCenterProductService) Backend() string {
+	return ProductBackendSellerCenter
+}
+
+func (s *sellerCenterProductService) ListProducts(ctx context.Context) ([]int64, error) {
+	return s.client.GetProductList(s.cookies, s.shopId, s.region, shopee.ListTypeAll)
+}
+
+func (s *sellerCenterProductService) GetProducts(ctx context.Context, itemIdList []int64) ([]ProductInfo, error) {
+	wanted := make(map[int64]bool, len(itemIdList))
+	for _, itemId := range itemIdList {
+		wanted[itemId] = true
+	}
+
+	// 使用带出货天数的商品列表，全部找到后不再请求已下架列表
+	var products []ProductInfo
+	for _, list := range []struct{ listType, status string }{
+		{shopee.ListTypeLive, shopee.TWItemStatusNormal},
+		{shopee.ListTypeDelisted, shopee.TWItemStatusUnlist},
+	} {
+		if len(wanted) == 0 {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		details, err := s.client.GetProductDetailListWithDayToShip(s.cookies, s.shopId, s.region, list.listType)
+		if err != nil {
+			return nil, err
+		}
+		for _, detail := range details {
+			itemId := int64(detail.ID)
+			if !wanted[itemId] {
+				continue
+			}
+			delete(wanted, itemId)
+			products = append(products, ProductInfo{
+				ItemId:     itemId,
+				Name:       detail.Name,
+				Status:     list.status,
+				IsPreOrder: detail.PreOrder,
+				DaysToShip: detail.DaysToShip,
+				CreateTime: detail.CreateTime,
+			})
+		}
+	}
+	return products, nil
+}
+
+// SetDaysToShip 修改出货天数并保持商品当前的上下架状态，店铺中不存在的商品记为失败
+func (s *sellerCenterProductService) SetDaysToShip(ctx context.Context, itemIdList []int64, daysToShip int) (*ProductOperationResult, error) {
+	if daysToShip <= 0 {
+		return nil, fmt.Errorf("出货天数必须大于 0")
+	}
+	products, err := s.GetProducts(ctx, itemIdList)
+	if err != nil {
+		return nil, fmt.Errorf("获取商品上下架状态失败: %w", err)
+	}
+	unlisted := make(map[int64]bool, len(products))
+	for _, product := range products {
+		unlisted[product.ItemId] = product.Status == shopee.TWItemStatusUnlist
+	}
+	return s.setDaysToShip(ctx, itemIdList, daysToShip, unlisted)
+}
+
+// setDaysToShip 按已知的上下架状态修改出货天数，unlisted 中没有的商品视为不存在
+func (s *sellerCenterProductService) setDaysToShip(ctx context.Context, itemIdList []int64, daysToShip int,
+	unlisted map[int64]bool) (*ProductOperationResult, error) {
+	result := &ProductOperationResult{}
+	items := make([]shopee.BatchUpdateProductInfoItem, 0, len(itemIdList))
+	for _, itemId := range itemIdList {
+		status, ok := unlisted[itemId]
+		if !ok {
+			result.fail([]int64{itemId}, "商品不存在")
+			continue
+		}
+		items = append(items, shopee.NewDaysToShipItem(itemId, daysToShip, status))
+	}
+	return s.batchUpdate(ctx, items, result)
+}
+
+func (s *sellerCenterProductService) SetListed(ctx context.Context, itemIdList []int64, listed bool) (*ProductOperationResult, error) {
+	action := shopee.ListingActionList
+	if !listed {
+		action = shopee.ListingActionUnlist
+	}
+	result := &ProductOperationResult{}
+	if len(itemIdList) == 0 {
+		return result, nil
+	}
+	outcomes, err := s.client.ListedOrUnlistedProducts(ctx, s.shopId, s.cookies, s.region, action, itemIdList,
+		shopee.ListingOptions{BatchSize: cnscBatchSize})
+	for _, outcome := range outcomes {
+		switch {
+		case outcome.Success:
+			result.Succeeded = append(result.Succeeded, outcome.ProductId)
+		case outcome.Attempts == 0 && err != nil:
+			result.Failed = append(result.Failed, ProductFailure{ItemId: outcome.ProductId, Reason: err.Error()})
+		default:
+			result.Failed = append(result.Failed, ProductFailure{ItemId: outcome.ProductId, Reason: outcome.Message})
+		}
+	}
+	return result, err
+}
+
+func (s *sellerCenterProductService) DeleteProducts(ctx context.Context, itemIdList []int64) (*ProductOperationResult, error) {
+	return s.deleteProducts(ctx, itemIdList, "product_service")
+}
+
+// deleteProducts 删除商品，operator 记录删除来源
+func (s *sellerCenterProductService) deleteProducts(ctx context.Context, itemIdList []int64, operator string) (*ProductOperationResult, error) {
+	return guardedDelete(ctx, s.deleteGuard, ProductDeleteRequest{
+		Cookies:    s.cookies,
+		ShopId:     s.shopId,
+		Region:     s.region,
+		ProductIds: itemIdList,
+		Operator:   operator,
+	})
+}
+
+// guardedDelete 通过删除保护删除商品，受保护与不存在的商品记为失败
+func guardedDelete(ctx context.Context, guard *ProductDeleteGuard, req ProductDeleteRequest) (*ProductOperationResult, error) {
+	if guard == nil {
+		return nil, fmt.Errorf("未配置删除保护，不能删除商品")
+	}
+	report, err := guard.Delete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProductOperationResult{Succeeded: report.Deleted, Failed: report.Failed}
+	result.fail(report.Protected, "商品在保护列表中")
+	result.fail(report.NotFound, "商品不存在")
+	return result, nil
+}
+
+// batchUpdate 分批调用 V3 批量更新接口，按返回的每个商品 code 判断成功与否，结果追加到 result
+func (s *sellerCenterProductService) batchUpdate(ctx context.Context, updates []shopee.BatchUpdateProductInfoItem,
+	result *ProductOperationResult) (*ProductOperationResult, error) {
+	req := shopee.UpdateProductInfoReq{Cookies: s.cookies, ShopID: s.shopId, Region: s.region}
+	for start := 0; start < len(updates); start += cnscBatchSize {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		batch := updates[start:minInt(start+cnscBatchSize, len(updates))]
+		batchIds := make([]int64, 0, len(batch))
+		for _, update := range batch {
+			batchIds = append(batchIds, update.ID)
+		}
+		items, err := s.client.BatchUpdateProductItems(req, batch, shopee.SourceSellerCenter)
+		if err != nil && len(items) == 0 {
+			result.fail(batchIds, err.Error())
+			continue
+		}
+
+		returned := make(map[int64]bool, len(items))
+		for _, item := range items {
+			returned[item.ID] = true
+			if item.Code != shopee.ResponseCodeSuccess {
+				result.Failed = append(result.Failed, ProductFailure{ItemId: item.ID, Reason: item.UserMessage})
+				continue
+			}
+			result.Succeeded = append(result.Succeeded, item.ID)
+		}
+		// 未返回结果的商品：整体成功时视为成功，否则记为失败
+		for _, itemId := range batchIds {
+			if returned[itemId] {
+				continue
+			}
+			if err != nil {
+				result.Failed = append(result.Failed, ProductFailure{ItemId: itemId, Reason: err.Error()})
+				continue
+			}
+			result.Succeeded = append(result.Succeeded, itemId)
+		}
+	}
+	return result, nil
+}
+
+// openPlatformProductService 基于 access_token 的 Open Platform 实现
+type openPlatformProductService struct {
+	client      *shopee.Client
+	tokens      TokenSource
+	shopId      string
+	deleteGuard *ProductDeleteGuard
+}
+
+func (s *openPlatformProductService) Backend() string {
+	return ProductBackendOpenPlatform
+}
+
+func (s *openPlatformProductService) auth(ctx context.Context) (shopee.OpenAPIAuth, error) {
+	accessToken, err := s.tokens.Token(ctx, s.shopId)
+	if err != nil {
+		return shopee.OpenAPIAuth{}, err
+	}
+	return shopee.OpenAPIAuth{AccessToken: accessToken, ShopId: s.shopId}, nil
+}
+
+func (s *openPlatformProductService) ListProducts(ctx context.Context) ([]int64, error) {
+	auth, err := s.auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.client.GetItemListWithAreaTw(ctx, auth, shopee.TWItemListFilter{
+		ItemStatus: []string{shopee.TWItemStatusNormal, shopee.TWItemStatusUnlist},
+	})
+	if err != nil {
+		return nil, err
+	}
+	itemIdList := make([]int64, 0, len(items))
+	for _, item := range items {
+		itemIdList = append(itemIdList, item.ItemId)
+	}
+	return itemIdList, nil
+}
+
+func (s *openPlatformProductService) GetProducts(ctx context.Context, itemIdList []int64) ([]ProductInfo, error) {
+	auth, err := s.auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.client.GetItemBaseInfoListWithAreaTw(ctx, auth, itemIdList)
+	if err != nil {
+		return nil, err
+	}
+	products := make([]ProductInfo, 0, len(items))
+	for _, item := range items {
+		products = append(products, ProductInfo{
+			ItemId:     item.ItemId,
+			Name:       item.ItemName,
+			Status:     item.ItemStatus,
+			IsPreOrder: item.PreOrder.IsPreOrder,
+			DaysToShip: item.PreOrder.DaysToShip,
+			CreateTime: item.CreateTime,
+		})
+	}
+	return products, nil
+}
+
+func (s *openPlatformProductService) SetDaysToShip(ctx context.Context, itemIdList []int64, daysToShip int) (*ProductOperationResult, error) {
+	if daysToShip <= 0 {
+		return nil, fmt.Errorf("出货天数必须大于 0")
+	}
+	auth, err := s.auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 与 CNSC 保持一致，2 天表示非预售
+	target := shopee.UpdateProductInfoWithAreaTwItem{DaysToShip: daysToShip, IsPreOrder: daysToShip != 2}
+	report, err := s.client.BulkUpdatePreOrderWithAreaTw(ctx, auth, itemIdList, target, shopee.TWBulkPreOrderOptions{})
+	if report == nil {
+		return nil, err
+	}
+
+	result := &ProductOperationResult{}
+	result.Succeeded = append(result.Succeeded, report.Updated...)
+	result.Succeeded = append(result.Succeeded, report.Unchanged...)
+	for _, failure := range report.Failed {
+		result.Failed = append(result.Failed, ProductFailure{ItemId: failure.ItemId, Reason: failure.Reason})
+	}
+	return result, err
+}
+
+func (s *openPlatformProductService) SetListed(ctx context.Context, itemIdList []int64, listed bool) (*ProductOperationResult, error) {
+	auth, err := s.auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	succeeded, failures, err := s.client.UnlistItemsWithAreaTw(ctx, auth, itemIdList, !listed)
+
+	result := &ProductOperationResult{Succeeded: succeeded}
+	for _, failure := range failures {
+		result.Failed = append(result.Failed, ProductFailure{ItemId: failure.ItemId, Reason: failure.Reason})
+	}
+	return result, err
+}
+
+func (s *openPlatformProductService) DeleteProducts(ctx context.Context, itemIdList []int64) (*ProductOperationResult, error) {
+	if s.deleteGuard == nil {
+		return nil, fmt.Errorf("未配置删除保护，不能删除商品")
+	}
+	auth, err := s.auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return guardedDelete(ctx, s.deleteGuard, ProductDeleteRequest{
+		Auth:       &auth,
+		ShopId:     s.shopId,
+		Region:     openPlatformRegion,
+		ProductIds: itemIdList,
+		Operator:   "product_service",
+	})
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
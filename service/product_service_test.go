@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+)
+
+func TestNewProductService(t *testing.T) {
+	client := shopee.NewTaiwanClient()
+
+	service, err := NewProductService(client, ShopCredentials{
+		ShopeeAccount: &model.ShopeeAccount{ShopId: "1", AccessToken: "token"},
+		Account:       &model.Account{Cookies: "SPC_EC=1;"},
+	})
+	if err != nil || service.Backend() != ProductBackendOpenPlatform {
+		t.Errorf("Token credentials should use open platform, got %v, %v", service, err)
+	}
+
+	service, err = NewProductService(client, ShopCredentials{
+		Account: &model.Account{Cookies: "SPC_EC=1;"},
+		ShopId:  "1",
+		Region:  "sg",
+	})
+	if err != nil || service.Backend() != ProductBackendSellerCenter {
+		t.Errorf("Cookie credentials should use seller center, got %v, %v", service, err)
+	}
+
+	inactive := &model.ShopeeAccount{ShopId: "1", AccessToken: "token", Status: model.ShopeeAccountStatusInactive}
+	if _, err := NewProductService(client, ShopCredentials{ShopeeAccount: inactive}); err == nil {
+		t.Error("Inactive account should be rejected")
+	}
+	if _, err := NewProductService(client, ShopCredentials{}); err == nil {
+		t.Error("Empty credentials should be rejected")
+	}
+}
+
+func TestOpenPlatformProductServiceSetListed(t *testing.T) {
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		var req shopee.TWUnlistItemReq
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.ItemList) != 2 || !req.ItemList[0].Unlist {
+			t.Errorf("Unexpected unlist request: %+v", req)
+		}
+		io.WriteString(w, `{"response":{"success_list":[{"item_id":1,"unlist":true}],"failure_list":[{"item_id":2,"failed_reason":"banned"}]}}`)
+	})
+	defer server.Close()
+
+	service, err := NewProductService(client, ShopCredentials{
+		ShopeeAccount: &model.ShopeeAccount{ShopId: "1", AccessToken: "token"},
+	})
+	if err != nil {
+		t.Fatalf("NewProductService() error = %v", err)
+	}
+	result, err := service.SetListed(context.Background(), []int64{1, 2}, false)
+	if err != nil {
+		t.Fatalf("SetListed() error = %v", err)
+	}
+	if len(result.Succeeded) != 1 || len(result.Failed) != 1 || result.Failed[0].Reason != "banned" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestSellerCenterProductServiceSetDaysToShip(t *testing.T) {
+	var sent []shopee.BatchUpdateProductInfoItem
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductDetailList:
+			if r.URL.Query().Get("list_type") == shopee.ListTypeLive {
+				io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":1,"name":"T恤","days_to_ship":2}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":2,"name":"帽子","days_to_ship":5,"pre_order":true}]}}`)
+		case shopee.APIPathBatchUpdateProductInfo:
+			json.NewDecoder(r.Body).Decode(&sent)
+			io.WriteString(w, `{"code":0,"data":{"result":[]}}`)
+		}
+	})
+	defer server.Close()
+
+	service, err := NewProductService(client, ShopCredentials{Account: &model.Account{Cookies: "SPC_EC=1;"}, ShopId: "100", Region: "sg"})
+	if err != nil {
+		t.Fatalf("NewProductService() error = %v", err)
+	}
+	products, err := service.GetProducts(context.Background(), []int64{2})
+	if err != nil || len(products) != 1 || !products[0].IsPreOrder || products[0].DaysToShip != 5 ||
+		products[0].Status != shopee.TWItemStatusUnlist {
+		t.Fatalf("GetProducts() = %+v, error = %v", products, err)
+	}
+
+	result, err := service.SetDaysToShip(context.Background(), []int64{1, 2, 3}, 7)
+	if err != nil {
+		t.Fatalf("SetDaysToShip() error = %v", err)
+	}
+	want := []shopee.BatchUpdateProductInfoItem{
+		{ID: 1, DaysToShip: 7, PreOrder: true},
+		{ID: 2, DaysToShip: 7, PreOrder: true, Unlisted: true},
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %+v, want %+v", sent, want)
+	}
+	if len(result.Succeeded) != 2 || len(result.Failed) != 1 || result.Failed[0].ItemId != 3 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+// ProductSnapshotStore 商品快照存储
+type ProductSnapshotStore interface {
+	CreateSnapshot(snapshot *model.ProductSnapshot, items []model.ProductSnapshotItem) error
+	GetSnapshot(id int64) (*model.ProductSnapshot, []model.ProductSnapshotItem, error)
+	MarkRolledBack(id int64) error
+}
+
+// ProductSnapshotter 在 CNSC 批量修改前记录商品状态，并支持按快照回滚
+type ProductSnapshotter struct {
+	client *shopee.Client
+	store  ProductSnapshotStore
+}
+
+// NewProductSnapshotter 创建快照工具，store 为空时使用数据库存储
+func NewProductSnapshotter(client *shopee.Client, store ProductSnapshotStore) *ProductSnapshotter {
+	if store == nil {
+		store = repository.NewProductSnapshotRepository()
+	}
+	return &ProductSnapshotter{client: client, store: store}
+}
+
+// Capture 记录商品当前的上下架状态、出货天数与预售标记，operation 为即将执行的批量操作
+// 在售与已下架列表中都找不到的商品不会写入快照
+func (s *ProductSnapshotter) Capture(cookies, shopId, region, operation string, productIds []int64) (*model.ProductSnapshot, error) {
+	wanted := make(map[int64]bool, len(productIds))
+	for _, productId := range productIds {
+		wanted[productId] = true
+	}
+
+	var items []model.ProductSnapshotItem
+	for _, list := range []struct {
+		listType string
+		unlisted bool
+	}{
+		{shopee.ListTypeLive, false},
+		{shopee.ListTypeDelisted, true},
+	} {
+		products, err := s.client.GetProductDetailListWithDayToShip(cookies, shopId, region, list.listType)
+		if err != nil {
+			return nil, fmt.Errorf("获取商品状态失败, list_type=%s: %w", list.listType, err)
+		}
+		for _, product := range products {
+			productId := int64(product.ID)
+			if !wanted[productId] {
+				continue
+			}
+			delete(wanted, productId)
+			items = append(items, model.ProductSnapshotItem{
+				ProductID:  productId,
+				Unlisted:   list.unlisted,
+				DaysToShip: product.DaysToShip,
+				PreOrder:   product.PreOrder,
+			})
+		}
+	}
+	if len(wanted) > 0 {
+		logger.Warn("部分商品未找到，未写入快照", zap.String("shop_id", shopId), zap.Int("missing", len(wanted)))
+	}
+
+	snapshot := &model.ProductSnapshot{
+		ShopID:    shopId,
+		Region:    region,
+		Operation: operation,
+	}
+	if err := s.store.CreateSnapshot(snapshot, items); err != nil {
+		return nil, fmt.Errorf("保存商品快照失败: %w", err)
+	}
+	logger.Info("商品快照已保存", zap.String("shop_id", shopId), zap.Int64("snapshot_id", snapshot.ID),
+		zap.Int("version", snapshot.Version), zap.Int("items", len(items)))
+	return snapshot, nil
+}
+
+// Rollback 按快照恢复商品的出货天数、预售标记与上下架状态，分批方式与结果格式与正向批量操作一致
+func (s *ProductSnapshotter) Rollback(ctx context.Context, snapshotID int64, cookies string) (*ProductOperationResult, error) {
+	snapshot, items, err := s.store.GetSnapshot(snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("获取商品快照失败: %w", err)
+	}
+	if snapshot.Status == model.ProductSnapshotStatusRolledBack {
+		return nil, fmt.Errorf("快照 %d 已回滚", snapshotID)
+	}
+
+	cnsc := &sellerCenterProductService{
+		client:  s.client,
+		cookies: cookies,
+		shopId:  snapshot.ShopID,
+		region:  snapshot.Region,
+	}
+
+	productIds := make([]int64, 0, len(items))
+	for _, item := range items {
+		productIds = append(productIds, item.ProductID)
+	}
+	// 批量修改接口会按请求中的 unlisted 修改上下架状态，恢复出货天数时带上商品当前的状态
+	current, err := cnsc.GetProducts(ctx, productIds)
+	if err != nil {
+		return nil, fmt.Errorf("获取商品当前状态失败: %w", err)
+	}
+	unlisted := make(map[int64]bool, len(current))
+	for _, product := range current {
+		unlisted[product.ItemId] = product.Status == shopee.TWItemStatusUnlist
+	}
+
+	// 同一商品可能在两类恢复中各失败一次，按商品合并结果
+	failed := make(map[int64]string)
+	merge := func(result *ProductOperationResult) {
+		for _, failure := range result.Failed {
+			failed[failure.ItemId] = failure.Reason
+		}
+	}
+
+	// 按快照中的出货天数与预售标记逐个恢复
+	restore := &ProductOperationResult{}
+	var updates []shopee.BatchUpdateProductInfoItem
+	listedGroups := make(map[bool][]int64)
+	for _, item := range items {
+		listedGroups[!item.Unlisted] = append(listedGroups[!item.Unlisted], item.ProductID)
+		if item.DaysToShip <= 0 {
+			continue
+		}
+		status, ok := unlisted[item.ProductID]
+		if !ok {
+			restore.fail([]int64{item.ProductID}, "商品不存在")
+			continue
+		}
+		updates = append(updates, shopee.BatchUpdateProductInfoItem{
+			ID:         item.ProductID,
+			DaysToShip: item.DaysToShip,
+			PreOrder:   item.PreOrder,
+			Unlisted:   status,
+		})
+	}
+	result, err := cnsc.batchUpdate(ctx, updates, restore)
+	if err != nil {
+		return nil, err
+	}
+	merge(result)
+
+	for _, listed := range []bool{true, false} {
+		if len(listedGroups[listed]) == 0 {
+			continue
+		}
+		result, err := cnsc.SetListed(ctx, listedGroups[listed], listed)
+		if err != nil {
+			return nil, err
+		}
+		merge(result)
+	}
+
+	report := &ProductOperationResult{}
+	for _, item := range items {
+		if reason, ok := failed[item.ProductID]; ok {
+			report.Failed = append(report.Failed, ProductFailure{ItemId: item.ProductID, Reason: reason})
+			continue
+		}
+		report.Succeeded = append(report.Succeeded, item.ProductID)
+	}
+
+	if len(report.Failed) == 0 {
+		if err := s.store.MarkRolledBack(snapshotID); err != nil {
+			return report, fmt.Errorf("更新快照状态失败: %w", err)
+		}
+	}
+	logger.Info("商品快照回滚完成", zap.Int64("snapshot_id", snapshotID),
+		zap.Int("succeeded", len(report.Succeeded)), zap.Int("failed", len(report.Failed)))
+	return report, nil
+}
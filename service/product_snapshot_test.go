@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+)
+
+type fakeSnapshotStore struct {
+	snapshot   *model.ProductSnapshot
+	items      []model.ProductSnapshotItem
+	rolledBack bool
+}
+
+func (s *fakeSnapshotStore) CreateSnapshot(snapshot *model.ProductSnapshot, items []model.ProductSnapshotItem) error {
+	snapshot.ID = 1
+	snapshot.Version = 1
+	s.snapshot = snapshot
+	s.items = items
+	return nil
+}
+
+func (s *fakeSnapshotStore) GetSnapshot(id int64) (*model.ProductSnapshot, []model.ProductSnapshotItem, error) {
+	return s.snapshot, s.items, nil
+}
+
+func (s *fakeSnapshotStore) MarkRolledBack(id int64) error {
+	s.rolledBack = true
+	return nil
+}
+
+func TestProductSnapshotter(t *testing.T) {
+	var updates [][]shopee.BatchUpdateProductInfoItem
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductDetailList:
+			if r.URL.Query().Get("list_type") == shopee.ListTypeLive {
+				io.WriteString(w, `{"code":0,"data":{"page_info":{"total":2},"list":[{"id":1,"days_to_ship":3,"pre_order":true},{"id":2,"days_to_ship":2}]}}`)
+				return
+			}
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"list":[{"id":3,"days_to_ship":7,"pre_order":true}]}}`)
+		case shopee.APIPathBatchUpdateProductInfo:
+			var req []shopee.BatchUpdateProductInfoItem
+			json.NewDecoder(r.Body).Decode(&req)
+			updates = append(updates, req)
+			io.WriteString(w, `{"code":0,"data":{"result":[]}}`)
+		}
+	})
+	defer server.Close()
+
+	store := &fakeSnapshotStore{}
+	snapshotter := NewProductSnapshotter(client, store)
+	snapshot, err := snapshotter.Capture("SPC_EC=1;", "100", "sg", "unlist", []int64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	if snapshot.ShopID != "100" || len(store.items) != 3 {
+		t.Fatalf("Expected 3 captured items, got %+v", store.items)
+	}
+
+	report, err := snapshotter.Rollback(context.Background(), snapshot.ID, "SPC_EC=1;")
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if len(report.Succeeded) != 3 || !store.rolledBack {
+		t.Errorf("Unexpected rollback report: %+v", report)
+	}
+	// 出货天数一次 + 上架、下架各一次
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 batch updates, got %d", len(updates))
+	}
+	days := make(map[int64]shopee.BatchUpdateProductInfoItem)
+	for _, item := range updates[0] {
+		days[item.ID] = item
+	}
+	want := map[int64]shopee.BatchUpdateProductInfoItem{
+		1: {ID: 1, DaysToShip: 3, PreOrder: true},
+		2: {ID: 2, DaysToShip: 2},
+		3: {ID: 3, DaysToShip: 7, PreOrder: true, Unlisted: true},
+	}
+	if !reflect.DeepEqual(days, want) {
+		t.Errorf("days to ship restore = %+v, want %+v", days, want)
+	}
+	last := updates[len(updates)-1]
+	if len(last) != 1 || last[0].ID != 3 || !last[0].Unlisted {
+		t.Errorf("Expected item 3 unlisted last, got %+v", last)
+	}
+}
+
+func TestProductSnapshotterCapturePageFailure(t *testing.T) {
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, `{"code":0,"data":{"page_info":{"total":60,"cursor":"next"},"list":[{"id":1,"days_to_ship":3}]}}`)
+	})
+	defer server.Close()
+
+	store := &fakeSnapshotStore{}
+	if _, err := NewProductSnapshotter(client, store).Capture("SPC_EC=1;", "100", "sg", "unlist", []int64{1}); err == nil {
+		t.Fatal("Capture() should fail when a page fails")
+	}
+	if store.snapshot != nil {
+		t.Errorf("incomplete snapshot should not be saved: %+v", store.items)
+	}
+}
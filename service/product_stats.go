@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+// ProductStatStore 商品每日统计存储
+type ProductStatStore interface {
+	SaveStatSnapshots(snapshots []model.ProductStatSnapshot) error
+	ListStatSnapshots(shopID, from, to string) ([]model.ProductStatSnapshot, error)
+}
+
+// ProductStatDelta 商品在时间窗口内的统计增量
+type ProductStatDelta struct {
+	ProductId   int64  `json:"product_id"`
+	FromDate    string `json:"from_date"` // 窗口内最早的快照日期
+	ToDate      string `json:"to_date"`   // 窗口内最晚的快照日期
+	LikesGained int64  `json:"likes_gained"`
+	SoldGained  int64  `json:"sold_gained"`
+	ViewsGained int64  `json:"views_gained"`
+}
+
+// Days 增量覆盖的天数，只有一次快照时为 0
+func (d ProductStatDelta) Days() int {
+	from, err1 := time.Parse(model.ProductStatSnapshotDateLayout, d.FromDate)
+	to, err2 := time.Parse(model.ProductStatSnapshotDateLayout, d.ToDate)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// IsZeroActivity 窗口内点赞、销量、浏览均无增长
+func (d ProductStatDelta) IsZeroActivity() bool {
+	return d.LikesGained == 0 && d.SoldGained == 0 && d.ViewsGained == 0
+}
+
+// ProductStatsCollector 采集商品每日统计并计算趋势
+type ProductStatsCollector struct {
+	client *shopee.Client
+	store  ProductStatStore
+}
+
+// NewProductStatsCollector 创建统计采集器，store 为空时使用数据库存储
+func NewProductStatsCollector(client *shopee.Client, store ProductStatStore) *ProductStatsCollector {
+	if store == nil {
+		store = repository.NewProductStatSnapshotRepository()
+	}
+	return &ProductStatsCollector{client: client, store: store}
+}
+
+// Collect 采集店铺在售与已下架商品的累计统计，记为 day 当天的快照，返回采集的商品数
+func (c *ProductStatsCollector) Collect(ctx context.Context, cookies, shopId, region string, day time.Time) (int, error) {
+	date := day.Format(model.ProductStatSnapshotDateLayout)
+	seen := make(map[int64]bool)
+	var snapshots []model.ProductStatSnapshot
+	for _, listType := range []string{shopee.ListTypeLive, shopee.ListTypeDelisted} {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		products, err := c.client.GetProductDetailList(cookies, shopId, region, listType)
+		if err != nil {
+			return 0, fmt.Errorf("获取商品列表失败, list_type=%s: %w", listType, err)
+		}
+		for _, product := range products {
+			productId := int64(product.ID)
+			if productId == 0 || seen[productId] {
+				continue
+			}
+			seen[productId] = true
+			snapshots = append(snapshots, model.ProductStatSnapshot{
+				ShopID:       shopId,
+				ProductID:    productId,
+				SnapshotDate: date,
+				LikedCount:   product.Statistics.LikedCount,
+				SoldCount:    product.Statistics.SoldCount,
+				ViewCount:    product.Statistics.ViewCount,
+			})
+		}
+	}
+	if err := c.store.SaveStatSnapshots(snapshots); err != nil {
+		return 0, fmt.Errorf("保存商品统计失败: %w", err)
+	}
+	logger.Info("商品统计采集完成", zap.String("shop_id", shopId), zap.String("date", date), zap.Int("products", len(snapshots)))
+	return len(snapshots), nil
+}
+
+// CollectShops 依次采集多个 CNSC 店铺，单个店铺失败不影响其他店铺，返回失败店铺及原因
+func (c *ProductStatsCollector) CollectShops(ctx context.Context, shops []ShopCredentials, day time.Time) map[string]error {
+	failed := make(map[string]error)
+	for _, shop := range shops {
+		if err := ctx.Err(); err != nil {
+			failed[shop.ShopId] = err
+			continue
+		}
+		if shop.Account == nil || shop.Account.Cookies == "" {
+			failed[shop.ShopId] = fmt.Errorf("店铺 %s 没有可用的 cookies", shop.ShopId)
+			continue
+		}
+		if _, err := c.Collect(ctx, shop.Account.Cookies, shop.ShopId, shop.Region, day); err != nil {
+			logger.Error("商品统计采集失败", zap.String("shop_id", shop.ShopId), zap.Error(err))
+			failed[shop.ShopId] = err
+		}
+	}
+	return failed
+}
+
+// Deltas 计算店铺商品在 [from, to] 日期内的统计增量
+func (c *ProductStatsCollector) Deltas(shopId string, from, to time.Time) ([]ProductStatDelta, error) {
+	snapshots, err := c.store.ListStatSnapshots(shopId,
+		from.Format(model.ProductStatSnapshotDateLayout), to.Format(model.ProductStatSnapshotDateLayout))
+	if err != nil {
+		return nil, fmt.Errorf("获取商品统计失败: %w", err)
+	}
+	return computeStatDeltas(snapshots), nil
+}
+
+// InactiveByTrend 返回在 [now-days, now] 内有足够快照覆盖且没有任何新增活动的商品
+// 快照覆盖不足 days 天的商品(新商品或采集中断)不会被判定为不活跃
+func (c *ProductStatsCollector) InactiveByTrend(shopId string, days int, now time.Time) ([]int64, error) {
+	deltas, err := c.Deltas(shopId, now.AddDate(0, 0, -days), now)
+	if err != nil {
+		return nil, err
+	}
+	var inactive []int64
+	for _, delta := range deltas {
+		if delta.Days() >= days && delta.IsZeroActivity() {
+			inactive = append(inactive, delta.ProductId)
+		}
+	}
+	return inactive, nil
+}
+
+// computeStatDeltas 以每个商品窗口内最晚与最早的快照相减，统计被修正导致的负增长按 0 计
+func computeStatDeltas(snapshots []model.ProductStatSnapshot) []ProductStatDelta {
+	type bounds struct{ first, last model.ProductStatSnapshot }
+	var order []int64
+	byProduct := make(map[int64]*bounds)
+	for _, snapshot := range snapshots {
+		b, ok := byProduct[snapshot.ProductID]
+		if !ok {
+			byProduct[snapshot.ProductID] = &bounds{first: snapshot, last: snapshot}
+			order = append(order, snapshot.ProductID)
+			continue
+		}
+		if snapshot.SnapshotDate < b.first.SnapshotDate {
+			b.first = snapshot
+		}
+		if snapshot.SnapshotDate > b.last.SnapshotDate {
+			b.last = snapshot
+		}
+	}
+
+	gained := func(from, to int64) int64 {
+		if to < from {
+			return 0
+		}
+		return to - from
+	}
+	deltas := make([]ProductStatDelta, 0, len(order))
+	for _, productId := range order {
+		b := byProduct[productId]
+		deltas = append(deltas, ProductStatDelta{
+			ProductId:   productId,
+			FromDate:    b.first.SnapshotDate,
+			ToDate:      b.last.SnapshotDate,
+			LikesGained: gained(b.first.LikedCount, b.last.LikedCount),
+			SoldGained:  gained(b.first.SoldCount, b.last.SoldCount),
+			ViewsGained: gained(b.first.ViewCount, b.last.ViewCount),
+		})
+	}
+	return deltas
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/pool"
+)
+
+type fakeStatStore struct {
+	snapshots []model.ProductStatSnapshot
+}
+
+func (s *fakeStatStore) SaveStatSnapshots(snapshots []model.ProductStatSnapshot) error {
+	s.snapshots = append(s.snapshots, snapshots...)
+	return nil
+}
+
+func (s *fakeStatStore) ListStatSnapshots(shopID, from, to string) ([]model.ProductStatSnapshot, error) {
+	var result []model.ProductStatSnapshot
+	for _, snapshot := range s.snapshots {
+		if snapshot.ShopID == shopID && snapshot.SnapshotDate >= from && snapshot.SnapshotDate <= to {
+			result = append(result, snapshot)
+		}
+	}
+	return result, nil
+}
+
+func TestProductStatsCollector(t *testing.T) {
+	pool.InitWorkerPool()
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list_type") == shopee.ListTypeLive {
+			io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":1,"statistics":{"sold_count":12,"view_count":300}}]}}`)
+			return
+		}
+		io.WriteString(w, `{"code":0,"data":{"page_info":{"total":1},"products":[{"id":2,"statistics":{"view_count":40}}]}}`)
+	})
+	defer server.Close()
+
+	now := time.Date(2024, 6, 30, 10, 0, 0, 0, time.Local)
+	store := &fakeStatStore{snapshots: []model.ProductStatSnapshot{
+		{ShopID: "100", ProductID: 1, SnapshotDate: "2024-05-01", SoldCount: 1, ViewCount: 100},
+		{ShopID: "100", ProductID: 1, SnapshotDate: "2024-06-01", SoldCount: 10, ViewCount: 200},
+		{ShopID: "100", ProductID: 2, SnapshotDate: "2024-06-01", ViewCount: 40},
+		{ShopID: "100", ProductID: 3, SnapshotDate: "2024-06-20", ViewCount: 5},
+	}}
+	collector := NewProductStatsCollector(client, store)
+	count, err := collector.Collect(context.Background(), "SPC_EC=1;", "100", "sg", now)
+	if err != nil || count != 2 {
+		t.Fatalf("Collect() count = %d, error = %v", count, err)
+	}
+
+	deltas, err := collector.Deltas("100", now.AddDate(0, 0, -29), now)
+	if err != nil {
+		t.Fatalf("Deltas() error = %v", err)
+	}
+	want := []ProductStatDelta{
+		{ProductId: 1, FromDate: "2024-06-01", ToDate: "2024-06-30", SoldGained: 2, ViewsGained: 100},
+		{ProductId: 2, FromDate: "2024-06-01", ToDate: "2024-06-30"},
+		{ProductId: 3, FromDate: "2024-06-20", ToDate: "2024-06-20"},
+	}
+	if !reflect.DeepEqual(deltas, want) {
+		t.Errorf("Deltas() = %+v, want %+v", deltas, want)
+	}
+
+	inactive, err := collector.InactiveByTrend("100", 29, now)
+	if err != nil {
+		t.Fatalf("InactiveByTrend() error = %v", err)
+	}
+	if !reflect.DeepEqual(inactive, []int64{2}) {
+		t.Errorf("InactiveByTrend() = %v, want [2]", inactive)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+// maxPushBodySize 推送消息体大小上限
+const maxPushBodySize = 1 << 20
+
+// PushHandler 推送事件处理函数，返回错误时响应 500 让 Shopee 重新推送
+type PushHandler func(ctx context.Context, event *shopee.PushEvent) error
+
+// ShopeeAccountDeactivator 取消授权时标记账号失效
+type ShopeeAccountDeactivator interface {
+	DeactivateShopeeAccount(shopId string) error
+}
+
+// TokenInvalidator 清除店铺 token 缓存, *TokenManager 实现了该接口
+type TokenInvalidator interface {
+	Invalidate(shopId string)
+}
+
+// PushReceiver 接收 Open Platform 推送，校验签名后按推送类型分发
+type PushReceiver struct {
+	client      *shopee.Client
+	callbackURL string
+	accounts    ShopeeAccountDeactivator
+	tokens      TokenInvalidator
+
+	mu       sync.RWMutex
+	handlers map[shopee.PushCode][]PushHandler
+}
+
+// PushReceiverOption PushReceiver 配置项
+type PushReceiverOption func(*PushReceiver)
+
+// WithPushAccountStore 设置取消授权时的账号存储
+func WithPushAccountStore(store ShopeeAccountDeactivator) PushReceiverOption {
+	return func(r *PushReceiver) {
+		r.accounts = store
+	}
+}
+
+// WithPushTokenInvalidator 取消授权时同时清除 token 缓存
+func WithPushTokenInvalidator(tokens TokenInvalidator) PushReceiverOption {
+	return func(r *PushReceiver) {
+		r.tokens = tokens
+	}
+}
+
+// NewPushReceiver 创建推送接收器，callbackURL 需与 Open Platform 后台配置的推送地址完全一致(参与签名)
+func NewPushReceiver(client *shopee.Client, callbackURL string, opts ...PushReceiverOption) *PushReceiver {
+	r := &PushReceiver{
+		client:      client,
+		callbackURL: callbackURL,
+		handlers:    make(map[shopee.PushCode][]PushHandler),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.accounts == nil {
+		r.accounts = repository.NewShopeeAccountRepository()
+	}
+	r.Handle(shopee.PushCodeShopDeauthorization, r.handleDeauthorization)
+	return r
+}
+
+// Handle 注册推送处理函数，同一推送类型可注册多个，按注册顺序执行
+func (r *PushReceiver) Handle(code shopee.PushCode, handler PushHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[code] = append(r.handlers[code], handler)
+}
+
+// ServeHTTP 校验签名并分发推送事件
+func (r *PushReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPushBodySize))
+	if err != nil {
+		http.Error(w, "读取推送内容失败", http.StatusBadRequest)
+		return
+	}
+	if !r.client.VerifyPushSignature(r.callbackURL, body, req.Header.Get("Authorization")) {
+		logger.Warn("推送签名校验失败", zap.String("remote_addr", req.RemoteAddr))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := shopee.ParsePushEvent(body)
+	if err != nil {
+		logger.Error("解析推送失败", zap.String("body", string(body)), zap.Error(err))
+		http.Error(w, "解析推送失败", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.dispatch(req.Context(), event); err != nil {
+		logger.Error("处理推送失败", zap.Int("code", int(event.Code)),
+			zap.String("shop_id", event.ShopId), zap.Error(err))
+		http.Error(w, "处理推送失败", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dispatch 依次执行该推送类型的处理函数
+func (r *PushReceiver) dispatch(ctx context.Context, event *shopee.PushEvent) error {
+	r.mu.RLock()
+	handlers := r.handlers[event.Code]
+	r.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		logger.Debug("未注册的推送类型", zap.Int("code", int(event.Code)), zap.String("shop_id", event.ShopId))
+		return nil
+	}
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleDeauthorization 店铺取消授权后标记账号失效并清除 token 缓存
+func (r *PushReceiver) handleDeauthorization(ctx context.Context, event *shopee.PushEvent) error {
+	if event.ShopId == "" {
+		logger.Warn("取消授权推送缺少 shop_id")
+		return nil
+	}
+	if err := r.accounts.DeactivateShopeeAccount(event.ShopId); err != nil {
+		return fmt.Errorf("标记店铺 %s 失效失败: %w", event.ShopId, err)
+	}
+	if r.tokens != nil {
+		r.tokens.Invalidate(event.ShopId)
+	}
+	logger.Info("店铺已取消授权", zap.String("shop_id", event.ShopId))
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+	"github.com/donghui12/shopee_tool_base/pkg/constant"
+)
+
+type fakeDeactivator struct {
+	shopIds []string
+}
+
+func (d *fakeDeactivator) DeactivateShopeeAccount(shopId string) error {
+	d.shopIds = append(d.shopIds, shopId)
+	return nil
+}
+
+func TestPushReceiver(t *testing.T) {
+	const callbackURL = "https://example.com/push"
+	accounts := &fakeDeactivator{}
+	receiver := NewPushReceiver(shopee.NewTaiwanClient(), callbackURL, WithPushAccountStore(accounts))
+
+	var banned *shopee.PushBannedItem
+	receiver.Handle(shopee.PushCodeBannedItem, func(ctx context.Context, event *shopee.PushEvent) error {
+		banned, _ = event.Data.(*shopee.PushBannedItem)
+		return nil
+	})
+
+	send := func(body, authorization string) int {
+		req := httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(body))
+		req.Header.Set("Authorization", authorization)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	sign := func(body string) string {
+		return shopee.SignOpenAPI(constant.PartnerKey, callbackURL, "|", body)
+	}
+
+	deauth := `{"code":2,"timestamp":1700000000,"data":{"shop_id":123,"partner_id":1}}`
+	if code := send(deauth, "forged"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for forged signature, got %d", code)
+	}
+	if len(accounts.shopIds) != 0 {
+		t.Fatal("forged push should not deactivate account")
+	}
+
+	if code := send(deauth, sign(deauth)); code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if len(accounts.shopIds) != 1 || accounts.shopIds[0] != "123" {
+		t.Errorf("Expected shop 123 deactivated, got %v", accounts.shopIds)
+	}
+
+	item := `{"code":6,"shop_id":123,"timestamp":1700000000,"data":{"item_id":99,"item_status":"BANNED"}}`
+	if code := send(item, sign(item)); code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if banned == nil || banned.ItemId != 99 {
+		t.Errorf("Expected banned item event, got %+v", banned)
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/donghui12/shopee_tool_base/model"
+	"github.com/donghui12/shopee_tool_base/pkg/logger"
+	"github.com/donghui12/shopee_tool_base/repository"
+)
+
+const (
+	// DefaultTokenRefreshMargin 距离过期多久开始刷新 access_token
+	DefaultTokenRefreshMargin = 30 * time.Minute
+	// DefaultTokenRefreshInterval 后台巡检间隔
+	DefaultTokenRefreshInterval = 5 * time.Minute
+)
+
+// TokenRefresher 使用 refresh_token 换取新 token, *shopee.Client 实现了该接口
+type TokenRefresher interface {
+	GetAccessTokenWithAreaTw(shopId, code, refreshToken string) (string, string, string, error)
+}
+
+// TokenStore 已授权店铺 token 的存储
+type TokenStore interface {
+	GetAuthShopeeAccounts() ([]model.ShopeeAccount, error)
+	GetShopeeAccountByShopId(shopId string) (*model.ShopeeAccount, error)
+	RefreshTokenIfMatch(id int64, oldRefreshToken, accessToken, refreshToken, expireTime string) (bool, error)
+}
+
+// TokenManager 维护 Open Platform 店铺的 access_token，在过期前自动刷新
+type TokenManager struct {
+	client   TokenRefresher
+	store    TokenStore
+	margin   time.Duration
+	interval time.Duration
+
+	mu       sync.Mutex
+	shopLock map[string]*sync.Mutex
+	accounts map[string]model.ShopeeAccount
+}
+
+// TokenManagerOption TokenManager 配置项
+type TokenManagerOption func(*TokenManager)
+
+// WithTokenRefreshMargin 设置提前刷新的时间
+func WithTokenRefreshMargin(margin time.Duration) TokenManagerOption {
+	return func(m *TokenManager) {
+		m.margin = margin
+	}
+}
+
+// WithTokenRefreshInterval 设置后台巡检间隔
+func WithTokenRefreshInterval(interval time.Duration) TokenManagerOption {
+	return func(m *TokenManager) {
+		m.interval = interval
+	}
+}
+
+// WithTokenStore 设置 token 存储
+func WithTokenStore(store TokenStore) TokenManagerOption {
+	return func(m *TokenManager) {
+		m.store = store
+	}
+}
+
+// NewTokenManager 创建 token 管理器
+func NewTokenManager(client TokenRefresher, opts ...TokenManagerOption) *TokenManager {
+	m := &TokenManager{
+		client:   client,
+		margin:   DefaultTokenRefreshMargin,
+		interval: DefaultTokenRefreshInterval,
+		shopLock: make(map[string]*sync.Mutex),
+		accounts: make(map[string]model.ShopeeAccount),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.store == nil {
+		m.store = repository.NewShopeeAccountRepository()
+	}
+	return m
+}
+
+// Token 返回店铺当前有效的 access_token，临近过期时会先刷新
+// 刷新失败但 token 尚未过期时返回当前 token
+func (m *TokenManager) Token(ctx context.Context, shopId string) (string, error) {
+	lock := m.lockFor(shopId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	account, cached := m.cachedAccount(shopId)
+	if !cached || m.needRefresh(account) {
+		// 刷新前以存储中的 token 为准，refresh_token 可能已被其他进程轮换
+		current, err := m.store.GetShopeeAccountByShopId(shopId)
+		if err != nil {
+			m.Invalidate(shopId)
+			return "", fmt.Errorf("获取店铺 %s 授权信息失败: %w", shopId, err)
+		}
+		account = *current
+	}
+	if account.IsInactive() || account.AccessToken == "" {
+		m.Invalidate(shopId)
+		return "", fmt.Errorf("店铺 %s 未授权或授权已失效", shopId)
+	}
+
+	if m.needRefresh(account) {
+		refreshed, err := m.refresh(account)
+		if err != nil {
+			m.Invalidate(shopId)
+			if expireTime, parseErr := account.ExpireTime(); parseErr == nil && time.Now().Before(expireTime) {
+				logger.Warn("刷新 token 失败，使用未过期的 token", zap.String("shop_id", shopId), zap.Error(err))
+				return account.AccessToken, nil
+			}
+			return "", err
+		}
+		account = refreshed
+	}
+	m.cacheAccount(account)
+	return account.AccessToken, nil
+}
+
+// RefreshAll 加载全部已授权店铺，刷新临近过期的 token
+func (m *TokenManager) RefreshAll(ctx context.Context) error {
+	accounts, err := m.store.GetAuthShopeeAccounts()
+	if err != nil {
+		return fmt.Errorf("获取已授权店铺失败: %w", err)
+	}
+
+	var refreshed, failed int
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if account.IsInactive() || account.RefreshToken == "" {
+			m.Invalidate(account.ShopId)
+			continue
+		}
+
+		// 数据库中的 token 为准，刷新时通过 refresh_token 比对避免覆盖其他进程的结果
+		lock := m.lockFor(account.ShopId)
+		lock.Lock()
+		if m.needRefresh(account) {
+			current, err := m.refresh(account)
+			if err != nil {
+				failed++
+				logger.Error("刷新 token 失败", zap.String("shop_id", account.ShopId), zap.Error(err))
+				m.Invalidate(account.ShopId)
+				lock.Unlock()
+				continue
+			}
+			refreshed++
+			account = current
+		}
+		m.cacheAccount(account)
+		lock.Unlock()
+	}
+
+	logger.Info("token 巡检完成", zap.Int("total", len(accounts)),
+		zap.Int("refreshed", refreshed), zap.Int("failed", failed))
+	return nil
+}
+
+// Invalidate 清除店铺的 token 缓存，下次获取时重新从存储加载
+func (m *TokenManager) Invalidate(shopId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accounts, shopId)
+}
+
+// Run 按巡检间隔在后台刷新 token，直到 ctx 结束
+func (m *TokenManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.RefreshAll(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("token 巡检失败", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// needRefresh 判断 token 是否需要刷新，过期时间无法解析时视为需要刷新
+func (m *TokenManager) needRefresh(account model.ShopeeAccount) bool {
+	expireTime, err := account.ExpireTime()
+	if err != nil {
+		return true
+	}
+	return time.Now().Add(m.margin).After(expireTime)
+}
+
+// refresh 刷新 token 并持久化，调用方需持有该店铺的锁
+func (m *TokenManager) refresh(account model.ShopeeAccount) (model.ShopeeAccount, error) {
+	accessToken, refreshToken, expireAt, err := m.client.GetAccessTokenWithAreaTw(account.ShopId, "", account.RefreshToken)
+	if err != nil {
+		return account, fmt.Errorf("刷新店铺 %s token 失败: %w", account.ShopId, err)
+	}
+
+	updated, err := m.store.RefreshTokenIfMatch(account.ID, account.RefreshToken, accessToken, refreshToken, expireAt)
+	if err != nil {
+		return account, fmt.Errorf("保存店铺 %s token 失败: %w", account.ShopId, err)
+	}
+	if !updated {
+		// 其他进程已先行刷新，以数据库中的 token 为准
+		current, err := m.store.GetShopeeAccountByShopId(account.ShopId)
+		if err != nil {
+			return account, fmt.Errorf("重新加载店铺 %s token 失败: %w", account.ShopId, err)
+		}
+		logger.Warn("token 已被其他进程刷新", zap.String("shop_id", account.ShopId))
+		return *current, nil
+	}
+
+	account.AccessToken = accessToken
+	account.RefreshToken = refreshToken
+	account.ExpiredAt = expireAt
+	logger.Info("token 刷新成功", zap.String("shop_id", account.ShopId), zap.String("expired_at", expireAt))
+	return account, nil
+}
+
+// lockFor 返回店铺级别的锁，保证同一店铺串行刷新
+func (m *TokenManager) lockFor(shopId string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, ok := m.shopLock[shopId]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.shopLock[shopId] = lock
+	}
+	return lock
+}
+
+func (m *TokenManager) cachedAccount(shopId string) (model.ShopeeAccount, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	account, ok := m.accounts[shopId]
+	return account, ok
+}
+
+func (m *TokenManager) cacheAccount(account model.ShopeeAccount) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[account.ShopId] = account
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/model"
+)
+
+type fakeTokenStore struct {
+	mu      sync.Mutex
+	account model.ShopeeAccount
+}
+
+func (s *fakeTokenStore) GetAuthShopeeAccounts() ([]model.ShopeeAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return []model.ShopeeAccount{s.account}, nil
+}
+
+func (s *fakeTokenStore) GetShopeeAccountByShopId(shopId string) (*model.ShopeeAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account := s.account
+	return &account, nil
+}
+
+func (s *fakeTokenStore) RefreshTokenIfMatch(id int64, oldRefreshToken, accessToken, refreshToken, expireTime string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.account.RefreshToken != oldRefreshToken {
+		return false, nil
+	}
+	s.account.AccessToken, s.account.RefreshToken, s.account.ExpiredAt = accessToken, refreshToken, expireTime
+	return true, nil
+}
+
+// fakeTokenRefresher 每次刷新返回新的 token，err 不为空时刷新失败
+type fakeTokenRefresher struct {
+	calls int32
+	err   error
+}
+
+func (r *fakeTokenRefresher) GetAccessTokenWithAreaTw(shopId, code, refreshToken string) (string, string, string, error) {
+	atomic.AddInt32(&r.calls, 1)
+	time.Sleep(10 * time.Millisecond)
+	if r.err != nil {
+		return "", "", "", r.err
+	}
+	return "access-new", "refresh-new", time.Now().Add(4 * time.Hour).Format(model.ExpiredAtLayout), nil
+}
+
+func newTestTokenAccount(expireIn time.Duration) model.ShopeeAccount {
+	return model.ShopeeAccount{
+		ID:           1,
+		ShopId:       "100",
+		AccessToken:  "access-old",
+		RefreshToken: "refresh-old",
+		ExpiredAt:    time.Now().Add(expireIn).Format(model.ExpiredAtLayout),
+	}
+}
+
+func TestTokenManagerRefresh(t *testing.T) {
+	store := &fakeTokenStore{account: newTestTokenAccount(time.Hour)}
+	refresher := &fakeTokenRefresher{}
+
+	// 距离过期 1 小时，未进入 30 分钟的刷新窗口
+	manager := NewTokenManager(refresher, WithTokenStore(store))
+	if token, err := manager.Token(context.Background(), "100"); err != nil || token != "access-old" || refresher.calls != 0 {
+		t.Fatalf("Token() = %q, %v, refresh calls = %d", token, err, refresher.calls)
+	}
+
+	// 刷新窗口为 2 小时时需要刷新，刷新结果写入存储并缓存
+	manager = NewTokenManager(refresher, WithTokenStore(store), WithTokenRefreshMargin(2*time.Hour))
+	for i := 0; i < 2; i++ {
+		if token, err := manager.Token(context.Background(), "100"); err != nil || token != "access-new" {
+			t.Fatalf("Token() = %q, %v", token, err)
+		}
+	}
+	if refresher.calls != 1 || store.account.RefreshToken != "refresh-new" {
+		t.Errorf("refresh calls = %d, stored account = %+v", refresher.calls, store.account)
+	}
+}
+
+func TestTokenManagerReloadBeforeRefresh(t *testing.T) {
+	store := &fakeTokenStore{account: newTestTokenAccount(time.Hour)}
+	refresher := &fakeTokenRefresher{}
+	manager := NewTokenManager(refresher, WithTokenStore(store), WithTokenRefreshMargin(2*time.Hour))
+	manager.cacheAccount(newTestTokenAccount(10 * time.Minute))
+
+	// 其他进程已轮换 refresh_token，缓存的 token 临近过期时以存储中的为准
+	store.account = newTestTokenAccount(4 * time.Hour)
+	store.account.AccessToken, store.account.RefreshToken = "access-other", "refresh-other"
+	if token, err := manager.Token(context.Background(), "100"); err != nil || token != "access-other" || refresher.calls != 0 {
+		t.Errorf("Token() = %q, %v, refresh calls = %d", token, err, refresher.calls)
+	}
+}
+
+func TestTokenManagerRefreshFailure(t *testing.T) {
+	store := &fakeTokenStore{account: newTestTokenAccount(10 * time.Minute)}
+	refresher := &fakeTokenRefresher{err: errors.New("refresh_token 无效")}
+	manager := NewTokenManager(refresher, WithTokenStore(store))
+
+	// 未过期时刷新失败仍返回当前 token，且不缓存
+	if token, err := manager.Token(context.Background(), "100"); err != nil || token != "access-old" {
+		t.Fatalf("Token() = %q, %v", token, err)
+	}
+	if _, ok := manager.cachedAccount("100"); ok {
+		t.Error("failed refresh should evict the cache")
+	}
+
+	store.account = newTestTokenAccount(-time.Minute)
+	if _, err := manager.Token(context.Background(), "100"); err == nil {
+		t.Error("expired token should fail when refresh fails")
+	}
+}
+
+func TestTokenManagerConcurrentRefresh(t *testing.T) {
+	store := &fakeTokenStore{account: newTestTokenAccount(10 * time.Minute)}
+	refresher := &fakeTokenRefresher{}
+	manager := NewTokenManager(refresher, WithTokenStore(store))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := manager.Token(context.Background(), "100"); err != nil || token != "access-new" {
+				t.Errorf("Token() = %q, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls := atomic.LoadInt32(&refresher.calls); calls != 1 {
+		t.Errorf("concurrent callers should refresh once, got %d", calls)
+	}
+}
+
+func TestShopeeAccountExpireTime(t *testing.T) {
+	expireAt := time.Date(2099, 1, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	account := model.ShopeeAccount{ExpiredAt: expireAt.Format(model.ExpiredAtLayout)}
+	if got, err := account.ExpireTime(); err != nil || !got.Equal(expireAt) {
+		t.Errorf("ExpireTime() = %v, %v, want %v", got, err, expireAt)
+	}
+
+	// 旧数据按本地时间解析
+	account.ExpiredAt = "2099-01-01 08:00:00"
+	want := time.Date(2099, 1, 1, 8, 0, 0, 0, time.Local)
+	if got, err := account.ExpireTime(); err != nil || !got.Equal(want) {
+		t.Errorf("ExpireTime() = %v, %v, want %v", got, err, want)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/donghui12/shopee_tool_base/client/shopee"
+)
+
+type fakeSyncStateStore struct {
+	marks map[string]int64
+}
+
+func (s *fakeSyncStateStore) GetHighWaterMark(shopID string) (int64, error) {
+	return s.marks[shopID], nil
+}
+
+func (s *fakeSyncStateStore) SaveHighWaterMark(shopID string, lastUpdateTime int64) error {
+	s.marks[shopID] = lastUpdateTime
+	return nil
+}
+
+func TestTWProductSyncerSync(t *testing.T) {
+	var updateTimeFrom string
+	client, server := newOpenPlatformTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shopee.APIPathProductListForTw:
+			updateTimeFrom = r.URL.Query().Get("update_time_from")
+			io.WriteString(w, `{"response":{"item":[{"item_id":1,"item_status":"NORMAL"},{"item_id":2,"item_status":"UNLIST"}],"has_next_page":false}}`)
+		case shopee.APIPathGetBaseProductInfo:
+			io.WriteString(w, `{"response":{"item_list":[{"item_id":1},{"item_id":2}]}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	from := time.Now().Add(-time.Hour).Unix()
+	states := &fakeSyncStateStore{marks: map[string]int64{"100": from}}
+	syncer := NewTWProductSyncer(client, staticTokenSource("token"), states)
+
+	// handle 失败时不推进同步进度
+	if _, err := syncer.Sync(context.Background(), "100", func(*TWProductSyncResult) error {
+		return errors.New("写入失败")
+	}); err == nil {
+		t.Fatal("Sync() should fail when handle fails")
+	}
+	if states.marks["100"] != from {
+		t.Errorf("high water mark moved to %d after failed handle", states.marks["100"])
+	}
+
+	result, err := syncer.Sync(context.Background(), "100", nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if updateTimeFrom != strconv.FormatInt(from, 10) || len(result.Items) != 2 {
+		t.Errorf("update_time_from = %s, items = %d", updateTimeFrom, len(result.Items))
+	}
+	// 保存的进度回退一段时间，下次同步与本次有重叠
+	if want := result.UpdateTimeTo - int64(TWProductSyncOverlap/time.Second); states.marks["100"] != want {
+		t.Errorf("high water mark = %d, want %d", states.marks["100"], want)
+	}
+
+	// 两次同步间隔小于重叠时间时进度不回退到上次起点之前
+	states.marks["100"] = time.Now().Unix()
+	result, err = syncer.Sync(context.Background(), "100", nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if states.marks["100"] != result.UpdateTimeFrom {
+		t.Errorf("high water mark = %d, want %d", states.marks["100"], result.UpdateTimeFrom)
+	}
+}
@@ -0,0 +1,32 @@
+-- 创建 listing_windows 表
+CREATE TABLE IF NOT EXISTS `listing_windows` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `region` varchar(16) NOT NULL COMMENT '店铺区域',
+    `name` varchar(128) DEFAULT NULL COMMENT '窗口名称',
+    `action` varchar(32) NOT NULL COMMENT '窗口期间的动作：unlist/list',
+    `all_products` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否作用于店铺全部商品',
+    `start_at` timestamp NOT NULL COMMENT '开始时间',
+    `end_at` timestamp NOT NULL COMMENT '结束时间',
+    `status` varchar(32) NOT NULL COMMENT '状态：scheduled/active/completed/expired/canceled',
+    `applied_at` timestamp NULL DEFAULT NULL COMMENT '执行时间',
+    `reverted_at` timestamp NULL DEFAULT NULL COMMENT '恢复时间',
+    `error` varchar(512) DEFAULT NULL COMMENT '最近一次错误',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_shop_id` (`shop_id`),
+    KEY `idx_status_time` (`status`, `start_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='定时上下架窗口表';
+
+-- 创建 listing_window_items 表
+CREATE TABLE IF NOT EXISTS `listing_window_items` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `window_id` bigint NOT NULL COMMENT '窗口ID',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `status` varchar(32) NOT NULL COMMENT '状态：pending/applied/skipped/failed/reverted',
+    `reason` varchar(512) DEFAULT NULL COMMENT '失败原因',
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_window_product` (`window_id`, `product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='定时上下架窗口商品表';
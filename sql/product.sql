@@ -0,0 +1,36 @@
+-- 创建 products 表
+CREATE TABLE IF NOT EXISTS `products` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `region` varchar(16) DEFAULT NULL COMMENT '店铺区域',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `name` varchar(512) DEFAULT NULL COMMENT '商品名称',
+    `status` varchar(32) NOT NULL COMMENT '状态：live/unlisted/deleted',
+    `days_to_ship` int NOT NULL DEFAULT '0' COMMENT '出货天数',
+    `pre_order` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否预售',
+    `liked_count` bigint NOT NULL DEFAULT '0' COMMENT '点赞数',
+    `sold_count` bigint NOT NULL DEFAULT '0' COMMENT '销量',
+    `view_count` bigint NOT NULL DEFAULT '0' COMMENT '浏览量',
+    `model_count` int NOT NULL DEFAULT '0' COMMENT '规格数',
+    `create_time` bigint NOT NULL DEFAULT '0' COMMENT '商品创建时间',
+    `last_synced_at` timestamp NULL DEFAULT NULL COMMENT '最近一次同步时间',
+    `removed_at` timestamp NULL DEFAULT NULL COMMENT '标记为删除的时间',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_shop_product` (`shop_id`, `product_id`),
+    KEY `idx_shop_status` (`shop_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品目录表';
+
+-- 创建 product_models 表
+CREATE TABLE IF NOT EXISTS `product_models` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `model_id` bigint NOT NULL COMMENT '规格ID',
+    `name` varchar(255) DEFAULT NULL COMMENT '规格名称',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_product_model` (`product_id`, `model_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品规格表';
@@ -0,0 +1,29 @@
+-- 创建 protected_products 表
+CREATE TABLE IF NOT EXISTS `protected_products` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `reason` varchar(255) DEFAULT NULL COMMENT '保护原因',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_shop_product` (`shop_id`, `product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='受保护商品表';
+
+-- 创建 product_deletions 表
+CREATE TABLE IF NOT EXISTS `product_deletions` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `request_id` varchar(64) NOT NULL COMMENT '删除请求ID',
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `region` varchar(16) DEFAULT NULL COMMENT '店铺区域',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `name` varchar(512) DEFAULT NULL COMMENT '商品名称',
+    `payload` text COMMENT '删除前的完整商品信息',
+    `status` varchar(32) NOT NULL COMMENT '状态：pending/deleted/failed',
+    `reason` varchar(512) DEFAULT NULL COMMENT '失败原因',
+    `operator` varchar(64) DEFAULT NULL COMMENT '操作人',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_request_id` (`request_id`),
+    KEY `idx_shop_created` (`shop_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品删除归档表';
@@ -0,0 +1,27 @@
+-- 创建 product_snapshots 表
+CREATE TABLE IF NOT EXISTS `product_snapshots` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `region` varchar(16) DEFAULT NULL COMMENT '店铺区域',
+    `version` int NOT NULL COMMENT '店铺内快照版本',
+    `operation` varchar(64) DEFAULT NULL COMMENT '触发快照的批量操作',
+    `item_count` int NOT NULL DEFAULT '0' COMMENT '商品数量',
+    `status` varchar(32) DEFAULT NULL COMMENT '状态：created/rolled_back',
+    `rolled_back_at` timestamp NULL DEFAULT NULL COMMENT '回滚时间',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_shop_version` (`shop_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品快照表';
+
+-- 创建 product_snapshot_items 表
+CREATE TABLE IF NOT EXISTS `product_snapshot_items` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `snapshot_id` bigint NOT NULL COMMENT '快照ID',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `unlisted` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否下架',
+    `days_to_ship` int NOT NULL DEFAULT '0' COMMENT '出货天数',
+    `pre_order` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否预售',
+    PRIMARY KEY (`id`),
+    KEY `idx_snapshot_id` (`snapshot_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品快照明细表';
@@ -0,0 +1,15 @@
+-- 创建 product_stat_snapshots 表
+CREATE TABLE IF NOT EXISTS `product_stat_snapshots` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `product_id` bigint NOT NULL COMMENT '商品ID',
+    `snapshot_date` varchar(10) NOT NULL COMMENT '采集日期 yyyy-mm-dd',
+    `liked_count` bigint NOT NULL DEFAULT '0' COMMENT '累计点赞数',
+    `sold_count` bigint NOT NULL DEFAULT '0' COMMENT '累计销量',
+    `view_count` bigint NOT NULL DEFAULT '0' COMMENT '累计浏览量',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_shop_product_date` (`shop_id`, `product_id`, `snapshot_date`),
+    KEY `idx_shop_date` (`shop_id`, `snapshot_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品每日统计快照表';
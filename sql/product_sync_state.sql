@@ -0,0 +1,11 @@
+-- 创建 product_sync_states 表
+CREATE TABLE IF NOT EXISTS `product_sync_states` (
+    `id` bigint NOT NULL AUTO_INCREMENT,
+    `shop_id` varchar(64) NOT NULL COMMENT '店铺ID',
+    `last_update_time` bigint NOT NULL DEFAULT '0' COMMENT '已同步到的商品更新时间',
+    `last_sync_at` timestamp NULL DEFAULT NULL COMMENT '最近一次同步时间',
+    `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_shop_id` (`shop_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品增量同步进度表';